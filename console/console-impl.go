@@ -59,6 +59,7 @@ func (c *Console) Init(readFile string, writeFile string, readAvail utils.VoidFu
 	c.handlerArg = callArg
 	c.putBusy = false
 	c.incoming = -1
+	c.eof = false
 	c.nextPoll = make(chan byte)
 
 	go pollFile(c)
@@ -112,7 +113,8 @@ func (c *Console) WriteDone() {
 }
 
 // GetChar is used to read a character from the input buffer, if there is any there.
-//	Either return the character, or EOF if none buffered.
+//	Either return the character, or EOF if none buffered.  Once the end
+//	of the input has been announced, there never is one again.
 func (c *Console) GetChar() (byte, error) {
 	if c.incoming == -1 {
 		return 0, io.EOF
@@ -146,7 +148,10 @@ func readFile(ch chan byte, f *os.File) {
 
 	for {
 		// otherwise, read character and tell user about it
-		if n, err := f.Read(char); err != nil {
+		if n, err := f.Read(char); err == io.EOF {
+			close(ch) // nothing more will ever be typed
+			return
+		} else if err != nil {
 			utils.Panic(err)
 		} else if n != 1 {
 			utils.Panic(errors.New("Not enough characters to read from file"))
@@ -161,9 +166,15 @@ func pollFile(c *Console) {
 	for {
 		<-c.nextPoll
 		// do nothing if character is already buffered
-		if c.incoming == -1 {
+		if c.incoming == -1 && !c.eof {
 			select {
-			case x := <-fileChan:
+			case x, ok := <-fileChan:
+				if !ok {
+					// announce the end of the input, like a character
+					c.eof = true
+					c.readHandler(c.handlerArg)
+					break
+				}
 				c.incoming = int(x)
 				global.Stats.NumConsoleCharsRead++
 				c.readHandler(c.handlerArg)
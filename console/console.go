@@ -31,6 +31,7 @@ type Console struct {
 	// If so, you can't do another one!
 	incoming int // Contains the character to be read,
 
+	eof      bool // Has the end of the input been reached?
 	nextPoll chan byte
 }
 
@@ -0,0 +1,64 @@
+package console
+
+import (
+	"io"
+
+	"github.com/yashsriv/go-nachos/threads/synch"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+var synchConsoleReadAvail = func(arg interface{}) {
+	arg.(*SynchConsole).readAvail.V()
+}
+
+var synchConsoleWriteDone = func(arg interface{}) {
+	arg.(*SynchConsole).writeDone.V()
+}
+
+// Init initializes the console device, and the synchronization needed to
+// share it.
+//
+//	"readFile" -- UNIX file simulating the keyboard (NULL -> use stdin)
+//	"writeFile" -- UNIX file simulating the display (NULL -> use stdout)
+func (sc *SynchConsole) Init(readFile string, writeFile string) {
+	sc.readAvail = &synch.Semaphore{}
+	sc.readAvail.Init("read avail", 0)
+	sc.writeDone = &synch.Semaphore{}
+	sc.writeDone.Init("write done", 0)
+	sc.readLock = &synch.Lock{}
+	sc.readLock.Init("synch console read lock")
+	sc.writeLock = &synch.Lock{}
+	sc.writeLock.Init("synch console write lock")
+
+	sc.console = &Console{}
+	sc.console.Init(readFile, writeFile, synchConsoleReadAvail, synchConsoleWriteDone, sc)
+	utils.RegisterCleanup(sc.Close)
+}
+
+// Close shuts the console device down
+func (sc *SynchConsole) Close() {
+	sc.console.Close()
+}
+
+// PutChar writes "ch" to the display, and waits until it is out.
+func (sc *SynchConsole) PutChar(ch byte) {
+	sc.writeLock.Acquire()
+	sc.console.PutChar(ch)
+	sc.writeDone.P()
+	sc.writeLock.Release()
+}
+
+// GetChar waits until a character is typed on the keyboard, and returns
+// it.
+//
+// Returns io.EOF once there is no more input.
+func (sc *SynchConsole) GetChar() (byte, error) {
+	sc.readLock.Acquire()
+	sc.readAvail.P()
+	ch, err := sc.console.GetChar()
+	if err == io.EOF {
+		sc.readAvail.V() // the next reader finds the end of the input too
+	}
+	sc.readLock.Release()
+	return ch, err
+}
@@ -0,0 +1,29 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package console
+
+import "github.com/yashsriv/go-nachos/interfaces"
+
+// SynchConsole provides synchronized access to the console device.
+//
+// The raw console is asynchronous: PutChar returns at once and an
+// interrupt tells us later that the character is out, and a character
+// which has been typed is announced by an interrupt as well.  SynchConsole
+// hides this behind PutChar and GetChar calls which block until they are
+// done, and lets only one thread at a time write (or read), so that
+// requests from different threads are not mixed up.
+type SynchConsole struct {
+	console interfaces.IConsole
+
+	readAvail interfaces.ISemaphore // V'ed when a character has arrived
+	writeDone interfaces.ISemaphore // V'ed when a character has been output
+
+	readLock  interfaces.ILock // only one reader at a time
+	writeLock interfaces.ILock // only one writer at a time
+}
+
+// Test if our console implements the necessary interface
+var _ interfaces.ISynchConsole = &SynchConsole{}
+
+// Implemented in console/synch-console-impl.go
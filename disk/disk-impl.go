@@ -66,8 +66,9 @@ func (d *Disk) Init(name string, callWhenDone utils.VoidFunction, callArg interf
 	}
 	binary.Write(d.file, binary.LittleEndian, magicNumber)
 	// need to write at end of file, so that reads will not return EOF
-	d.file.Seek(-8, 2)
-	binary.Write(d.file, binary.LittleEndian, 0)
+	var diskSize = binary.Size(magicNumber) + NumSectors*SectorSize
+	d.file.Seek(int64(diskSize-binary.Size(int32(0))), 0)
+	binary.Write(d.file, binary.LittleEndian, int32(0))
 	d.active = false
 }
 
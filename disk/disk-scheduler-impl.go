@@ -0,0 +1,194 @@
+package disk
+
+import (
+	"container/list"
+	"fmt"
+
+	"github.com/yashsriv/go-nachos/interfaces"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// schedulingPolicy is the disk head scheduling policy of disks
+// initialized from now on
+var schedulingPolicy = "fcfs"
+
+// newScheduler returns a scheduler for the policy called "name", or nil
+// if there is no such policy
+func newScheduler(name string) interfaces.IDiskScheduler {
+	switch name {
+	case "fcfs":
+		return &FCFSScheduler{}
+	case "sstf":
+		return &SSTFScheduler{}
+	case "scan":
+		return &SCANScheduler{}
+	case "cscan":
+		return &CSCANScheduler{}
+	}
+	return nil
+}
+
+// SetSchedulingPolicy selects the disk head scheduling policy by name --
+// one of "fcfs", "sstf", "scan" or "cscan".  It applies to the disks
+// initialized afterwards.
+func SetSchedulingPolicy(name string) error {
+	if newScheduler(name) == nil {
+		return fmt.Errorf("Unknown disk scheduling policy %q", name)
+	}
+	schedulingPolicy = name
+	return nil
+}
+
+// removeBest takes out of "queue" the request for which "better" holds
+// against all the others, the earliest one in case of a tie.
+//
+// Returns nil if no request is acceptable to "ok".
+func removeBest(queue *list.List, ok func(sector int) bool, better func(a, b int) bool) interfaces.IDiskRequest {
+	var best *list.Element
+	for e := queue.Front(); e != nil; e = e.Next() {
+		sector := e.Value.(interfaces.IDiskRequest).Sector()
+		if ok(sector) && (best == nil || better(sector, best.Value.(interfaces.IDiskRequest).Sector())) {
+			best = e
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return queue.Remove(best).(interfaces.IDiskRequest)
+}
+
+func distance(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// Init initializes the FCFS queue
+func (s *FCFSScheduler) Init() {
+	s.queue = list.New()
+}
+
+// Name returns the name of the policy
+func (s *FCFSScheduler) Name() string {
+	return "fcfs"
+}
+
+// Add puts "request" at the end of the queue
+func (s *FCFSScheduler) Add(request interfaces.IDiskRequest) {
+	s.queue.PushBack(request)
+}
+
+// Next returns the request at the head of the queue
+func (s *FCFSScheduler) Next(head int) interfaces.IDiskRequest {
+	return s.queue.Remove(s.queue.Front()).(interfaces.IDiskRequest)
+}
+
+// Len returns the number of requests waiting
+func (s *FCFSScheduler) Len() int {
+	return s.queue.Len()
+}
+
+// Init initializes the SSTF queue
+func (s *SSTFScheduler) Init() {
+	s.queue = list.New()
+}
+
+// Name returns the name of the policy
+func (s *SSTFScheduler) Name() string {
+	return "sstf"
+}
+
+// Add puts "request" in the queue
+func (s *SSTFScheduler) Add(request interfaces.IDiskRequest) {
+	s.queue.PushBack(request)
+}
+
+// Next returns the request closest to the head
+func (s *SSTFScheduler) Next(head int) interfaces.IDiskRequest {
+	return removeBest(s.queue,
+		func(sector int) bool { return true },
+		func(a, b int) bool { return distance(a, head) < distance(b, head) })
+}
+
+// Len returns the number of requests waiting
+func (s *SSTFScheduler) Len() int {
+	return s.queue.Len()
+}
+
+// Init initializes the SCAN queue, with the head moving up
+func (s *SCANScheduler) Init() {
+	s.queue = list.New()
+	s.up = true
+}
+
+// Name returns the name of the policy
+func (s *SCANScheduler) Name() string {
+	return "scan"
+}
+
+// Add puts "request" in the queue
+func (s *SCANScheduler) Add(request interfaces.IDiskRequest) {
+	s.queue.PushBack(request)
+}
+
+// Next returns the closest request ahead of the head, turning the head
+// around if there is none
+func (s *SCANScheduler) Next(head int) interfaces.IDiskRequest {
+	utils.Assert(s.queue.Front() != nil, "There should be a request waiting")
+	for {
+		var request interfaces.IDiskRequest
+		if s.up {
+			request = removeBest(s.queue,
+				func(sector int) bool { return sector >= head },
+				func(a, b int) bool { return a < b })
+		} else {
+			request = removeBest(s.queue,
+				func(sector int) bool { return sector <= head },
+				func(a, b int) bool { return a > b })
+		}
+		if request != nil {
+			return request
+		}
+		s.up = !s.up
+	}
+}
+
+// Len returns the number of requests waiting
+func (s *SCANScheduler) Len() int {
+	return s.queue.Len()
+}
+
+// Init initializes the C-SCAN queue
+func (s *CSCANScheduler) Init() {
+	s.queue = list.New()
+}
+
+// Name returns the name of the policy
+func (s *CSCANScheduler) Name() string {
+	return "cscan"
+}
+
+// Add puts "request" in the queue
+func (s *CSCANScheduler) Add(request interfaces.IDiskRequest) {
+	s.queue.PushBack(request)
+}
+
+// Next returns the closest request ahead of the head, or the lowest one
+// if there is none
+func (s *CSCANScheduler) Next(head int) interfaces.IDiskRequest {
+	request := removeBest(s.queue,
+		func(sector int) bool { return sector >= head },
+		func(a, b int) bool { return a < b })
+	if request == nil {
+		request = removeBest(s.queue,
+			func(sector int) bool { return true },
+			func(a, b int) bool { return a < b })
+	}
+	return request
+}
+
+// Len returns the number of requests waiting
+func (s *CSCANScheduler) Len() int {
+	return s.queue.Len()
+}
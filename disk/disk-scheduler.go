@@ -0,0 +1,43 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package disk
+
+import (
+	"container/list"
+
+	"github.com/yashsriv/go-nachos/interfaces"
+)
+
+// FCFSScheduler serves requests in the order they arrive
+type FCFSScheduler struct {
+	queue *list.List
+}
+
+// SSTFScheduler serves the request closest to the disk head first
+// (shortest seek time first).  Requests far from the head can starve.
+type SSTFScheduler struct {
+	queue *list.List
+}
+
+// SCANScheduler moves the disk head like an elevator -- it serves the
+// requests in the direction the head is moving, and turns around when
+// there are no more requests ahead.
+type SCANScheduler struct {
+	queue *list.List
+	up    bool // true if the head is moving towards higher sectors
+}
+
+// CSCANScheduler serves requests only while the head moves towards higher
+// sectors; when there are no more requests ahead, it goes back to the
+// lowest sector requested.  Waiting times are more uniform than with SCAN.
+type CSCANScheduler struct {
+	queue *list.List
+}
+
+var _ interfaces.IDiskScheduler = &FCFSScheduler{}
+var _ interfaces.IDiskScheduler = &SSTFScheduler{}
+var _ interfaces.IDiskScheduler = &SCANScheduler{}
+var _ interfaces.IDiskScheduler = &CSCANScheduler{}
+
+// Implemented in disk-scheduler-impl.go
@@ -0,0 +1,103 @@
+package disk
+
+import (
+	"github.com/yashsriv/go-nachos/enums"
+	"github.com/yashsriv/go-nachos/global"
+	"github.com/yashsriv/go-nachos/threads/synch"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+var synchDiskRequestDone = func(arg interface{}) {
+	arg.(*SynchDisk).requestDone()
+}
+
+// Init initializes the disk simulated by the UNIX file "name", and the
+// queue of requests waiting for it.
+func (sd *SynchDisk) Init(name string) {
+	sd.scheduler = newScheduler(schedulingPolicy)
+	sd.scheduler.Init()
+	global.Stats.DiskSchedulingPolicy = sd.scheduler.Name()
+	sd.current = nil
+	sd.head = 0
+
+	sd.disk = &Disk{}
+	sd.disk.Init(name, synchDiskRequestDone, sd)
+	utils.RegisterCleanup(sd.Close)
+}
+
+// Close shuts the disk device down
+func (sd *SynchDisk) Close() {
+	sd.disk.Close()
+}
+
+// ReadSector reads the contents of sector "sectorNumber" into "data",
+// and waits until the disk is done.
+func (sd *SynchDisk) ReadSector(sectorNumber int, data []byte) {
+	sd.request(sectorNumber, data, false)
+}
+
+// WriteSector writes "data" to sector "sectorNumber", and waits until
+// the disk is done.
+func (sd *SynchDisk) WriteSector(sectorNumber int, data []byte) {
+	sd.request(sectorNumber, data, true)
+}
+
+// request sends a request to the disk, or queues it if the disk is
+// busy, and waits until it is done.  Interrupts are disabled while the
+// queue is looked at, since the disk interrupt handler takes requests out
+// of it.
+func (sd *SynchDisk) request(sectorNumber int, data []byte, writing bool) {
+	utils.Assert((sectorNumber >= 0) && (sectorNumber < NumSectors), "Sector number must be within the range of sectors")
+	req := &diskRequest{
+		sector:  sectorNumber,
+		data:    data,
+		writing: writing,
+		issued:  global.Stats.TotalTicks,
+		done:    &synch.Semaphore{},
+	}
+	req.done.Init("disk request done", 0)
+
+	oldLevel := global.Interrupt.SetLevel(enums.IntOff)
+	if sd.current == nil {
+		sd.start(req)
+	} else {
+		utils.Debug('d', "Disk busy, queueing request for sector %d, %d waiting\n", sectorNumber, sd.scheduler.Len())
+		sd.scheduler.Add(req)
+	}
+	global.Interrupt.SetLevel(oldLevel)
+	req.done.P() // wait for interrupt
+}
+
+// start sends "req" to the disk, which must be idle
+func (sd *SynchDisk) start(req *diskRequest) {
+	wait := global.Stats.TotalTicks - req.issued
+	if wait > global.Stats.MaxDiskWait {
+		global.Stats.MaxDiskWait = wait
+	}
+	global.Stats.DiskSeekDistance += distance(req.sector/SectorsPerTrack, sd.head/SectorsPerTrack)
+	sd.head = req.sector
+	sd.current = req
+	if req.writing {
+		sd.disk.WriteRequest(req.sector, req.data)
+	} else {
+		sd.disk.ReadRequest(req.sector, req.data)
+	}
+}
+
+// requestDone is called by the disk interrupt handler.  It wakes up the
+// thread waiting for the request just done, and sends the disk the next
+// request chosen by the scheduler.
+func (sd *SynchDisk) requestDone() {
+	req := sd.current
+	global.Stats.DiskLatency += global.Stats.TotalTicks - req.issued
+	sd.current = nil
+	if sd.scheduler.Len() > 0 {
+		sd.start(sd.scheduler.Next(sd.head).(*diskRequest))
+	}
+	req.done.V()
+}
+
+// Sector returns the sector the request is for
+func (req *diskRequest) Sector() int {
+	return req.sector
+}
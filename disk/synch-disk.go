@@ -0,0 +1,38 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package disk
+
+import "github.com/yashsriv/go-nachos/interfaces"
+
+// SynchDisk provides synchronized access to the disk device.
+//
+// The raw disk is asynchronous: a request returns at once, and an
+// interrupt tells us later that it is done.  Besides, it takes only one
+// request at a time.  SynchDisk hides this behind ReadSector and
+// WriteSector calls which block until the request is done.  Requests
+// made while the disk is busy wait in a queue, and the disk scheduler
+// decides in which order they are served.
+type SynchDisk struct {
+	disk interfaces.IDisk
+
+	scheduler interfaces.IDiskScheduler // requests waiting for the disk
+	current   *diskRequest              // request the disk is working on, nil if idle
+	head      int                       // sector of the last request sent to the disk
+}
+
+// diskRequest is a request to read or write a sector, made by a thread
+// which waits until it is done
+type diskRequest struct {
+	sector  int
+	data    []byte
+	writing bool
+	issued  int                   // time the request was made
+	done    interfaces.ISemaphore // V'ed once the disk is done with the request
+}
+
+// Test if our disk implements the necessary interface
+var _ interfaces.ISynchDisk = &SynchDisk{}
+var _ interfaces.IDiskRequest = &diskRequest{}
+
+// Implemented in synch-disk-impl.go
@@ -0,0 +1,110 @@
+package filesys
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/yashsriv/go-nachos/interfaces"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// directoryFileSize is the number of bytes a directory takes in its file
+var directoryFileSize = binary.Size(DirectoryEntry{}) * NumDirEntries
+
+// Init initializes a directory with space for "size" files, all empty.
+func (dir *Directory) Init(size int) {
+	dir.table = make([]DirectoryEntry, size)
+}
+
+// FetchFrom reads the contents of the directory from "file".
+func (dir *Directory) FetchFrom(file interfaces.IOpenFile) {
+	data := make([]byte, binary.Size(dir.table))
+	file.ReadAt(data, 0)
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, dir.table); err != nil {
+		utils.Panic(err)
+	}
+}
+
+// WriteBack writes the contents of the directory to "file".
+func (dir *Directory) WriteBack(file interfaces.IOpenFile) {
+	buf := &bytes.Buffer{}
+	if err := binary.Write(buf, binary.LittleEndian, dir.table); err != nil {
+		utils.Panic(err)
+	}
+	file.WriteAt(buf.Bytes(), 0)
+}
+
+// entryName returns the name of a directory entry as a string
+func entryName(entry *DirectoryEntry) string {
+	n := bytes.IndexByte(entry.Name[:], 0)
+	if n == -1 {
+		n = len(entry.Name)
+	}
+	return string(entry.Name[:n])
+}
+
+// findIndex looks up file name in the directory, and returns its location
+// in the table of directory entries.  Returns -1 if the name isn't in the
+// directory.
+func (dir *Directory) findIndex(name string) int {
+	for i := range dir.table {
+		if dir.table[i].InUse && entryName(&dir.table[i]) == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Find looks up file name in the directory, and returns the disk sector
+// number where the file's header is stored.  Returns -1 if the name isn't
+// in the directory.
+func (dir *Directory) Find(name string) int {
+	i := dir.findIndex(name)
+	if i == -1 {
+		return -1
+	}
+	return int(dir.table[i].Sector)
+}
+
+// Add adds a file into the directory.  Returns false if the file name is
+// already in the directory, is too long, or if the directory is
+// completely full and has no more space for additional file names.
+//
+//	"name" -- the name of the file being added
+//	"newSector" -- the disk sector containing the added file's header
+func (dir *Directory) Add(name string, newSector int) bool {
+	if len(name) == 0 || len(name) > FileNameMaxLen || dir.findIndex(name) != -1 {
+		return false
+	}
+	for i := range dir.table {
+		if !dir.table[i].InUse {
+			dir.table[i].InUse = true
+			dir.table[i].Name = [FileNameMaxLen + 1]byte{}
+			copy(dir.table[i].Name[:], name)
+			dir.table[i].Sector = int32(newSector)
+			return true
+		}
+	}
+	return false // no space
+}
+
+// Remove removes a file name from the directory.  Returns false if the
+// file isn't in the directory.
+func (dir *Directory) Remove(name string) bool {
+	i := dir.findIndex(name)
+	if i == -1 {
+		return false // name not in directory
+	}
+	dir.table[i].InUse = false
+	return true
+}
+
+// List lists all the file names in the directory.
+func (dir *Directory) List() {
+	for i := range dir.table {
+		if dir.table[i].InUse {
+			fmt.Printf("%s\n", entryName(&dir.table[i]))
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package filesys
+
+// Constants for directories
+const (
+	// FileNameMaxLen is the longest a file name can be
+	FileNameMaxLen = 9
+	// NumDirEntries is the number of files the directory can hold
+	NumDirEntries = 10
+)
+
+// DirectoryEntry defines a "directory entry", representing a file in
+// the directory.  Each entry gives the name of the file, and where the
+// file's header is to be found on disk.
+type DirectoryEntry struct {
+	InUse  bool                     // Is this directory entry in use?
+	Sector int32                    // Location on disk to find the FileHeader for this file
+	Name   [FileNameMaxLen + 1]byte // Text name for file, with +1 for the trailing NUL
+}
+
+// Directory is a table of pairs: <file name, sector #>, giving the name
+// of each file in the directory, and where to find its file header (the
+// data structure describing where to find the file's data blocks) on
+// disk.
+//
+// The directory has a fixed number of entries, and is itself stored in a
+// file.  There is a single, flat directory for the whole disk.
+type Directory struct {
+	table []DirectoryEntry
+}
+
+// Implemented in directory-impl.go
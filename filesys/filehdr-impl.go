@@ -0,0 +1,106 @@
+package filesys
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/yashsriv/go-nachos/disk"
+	"github.com/yashsriv/go-nachos/interfaces"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// divRoundUp returns how many blocks of "size" bytes are needed to hold
+// "n" bytes
+func divRoundUp(n, size int) int {
+	return (n + size - 1) / size
+}
+
+// Allocate initializes a fresh file header for a newly created file,
+// allocating data sectors for "fileSize" bytes out of the bitmap of free
+// disk sectors.  The data sectors are zeroed on "synchDisk".
+//
+// Returns false if there are not enough free sectors, or the file would
+// be too big.
+func (hdr *FileHeader) Allocate(synchDisk interfaces.ISynchDisk, freeMap *utils.BitMap, fileSize int) bool {
+	hdr.NumBytes = 0
+	hdr.NumSectors = 0
+	return hdr.Extend(synchDisk, freeMap, fileSize)
+}
+
+// Extend grows the file to "fileSize" bytes, allocating the data sectors
+// missing out of the bitmap of free disk sectors.  Nothing is allocated if
+// the file can't grow that much.  The new sectors are zeroed on
+// "synchDisk", so that the file never shows what a removed file left on
+// the disk.
+//
+// Returns false if there are not enough free sectors, or the file would
+// be too big.
+func (hdr *FileHeader) Extend(synchDisk interfaces.ISynchDisk, freeMap *utils.BitMap, fileSize int) bool {
+	if fileSize <= int(hdr.NumBytes) {
+		return true
+	}
+	numSectors := divRoundUp(fileSize, disk.SectorSize)
+	if fileSize > MaxFileSize || freeMap.NumClear() < numSectors-int(hdr.NumSectors) {
+		return false // not enough space
+	}
+	zeroes := make([]byte, disk.SectorSize)
+	for i := int(hdr.NumSectors); i < numSectors; i++ {
+		hdr.DataSectors[i] = int32(freeMap.Find())
+		synchDisk.WriteSector(int(hdr.DataSectors[i]), zeroes)
+	}
+	hdr.NumSectors = int32(numSectors)
+	hdr.NumBytes = int32(fileSize)
+	return true
+}
+
+// Deallocate gives back all the data sectors of the file to the bitmap
+// of free disk sectors.
+func (hdr *FileHeader) Deallocate(freeMap *utils.BitMap) {
+	for i := 0; i < int(hdr.NumSectors); i++ {
+		sector := int(hdr.DataSectors[i])
+		utils.Assert(freeMap.Test(sector), "A data sector of the file should be in use")
+		freeMap.Clear(sector)
+	}
+}
+
+// FetchFrom reads the file header in "sector" from disk.
+func (hdr *FileHeader) FetchFrom(synchDisk interfaces.ISynchDisk, sector int) {
+	data := make([]byte, disk.SectorSize)
+	synchDisk.ReadSector(sector, data)
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, hdr); err != nil {
+		utils.Panic(err)
+	}
+}
+
+// WriteBack writes the file header to "sector" on disk.
+func (hdr *FileHeader) WriteBack(synchDisk interfaces.ISynchDisk, sector int) {
+	buf := &bytes.Buffer{}
+	if err := binary.Write(buf, binary.LittleEndian, hdr); err != nil {
+		utils.Panic(err)
+	}
+	data := make([]byte, disk.SectorSize)
+	copy(data, buf.Bytes())
+	synchDisk.WriteSector(sector, data)
+}
+
+// ByteToSector returns which disk sector is storing the byte at "offset"
+// in the file.
+func (hdr *FileHeader) ByteToSector(offset int) int {
+	return int(hdr.DataSectors[offset/disk.SectorSize])
+}
+
+// FileLength returns the number of bytes in the file.
+func (hdr *FileHeader) FileLength() int {
+	return int(hdr.NumBytes)
+}
+
+// Print prints the file header, and the sectors it points to, for
+// debugging.
+func (hdr *FileHeader) Print() {
+	fmt.Printf("FileHeader contents.  File size: %d.  File blocks:\n", hdr.NumBytes)
+	for i := 0; i < int(hdr.NumSectors); i++ {
+		fmt.Printf("%d ", hdr.DataSectors[i])
+	}
+	fmt.Printf("\n")
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package filesys
+
+import "github.com/yashsriv/go-nachos/disk"
+
+// Constants for file headers
+const (
+	// NumDirect is the number of data sectors a header can point to, so
+	// that the header fits exactly in a disk sector
+	NumDirect = (disk.SectorSize - 2*4) / 4
+	// MaxFileSize is the largest a file can be
+	MaxFileSize = NumDirect * disk.SectorSize
+)
+
+// FileHeader (also called an "i-node") describes where on disk to find
+// all of the data in the file.  It is stored in its own disk sector, and
+// points directly to each data sector of the file.  The file header can
+// grow the file, up to MaxFileSize bytes, but it never gets any bigger
+// itself.
+//
+// A file header can be initialized in two ways:
+//
+//	for a new file, by allocating data sectors for it
+//
+//	for a file already on disk, by reading the header from disk
+type FileHeader struct {
+	NumBytes    int32            // Number of bytes in the file
+	NumSectors  int32            // Number of data sectors in the file
+	DataSectors [NumDirect]int32 // Disk sector numbers for each data block in the file
+}
+
+// Implemented in filehdr-impl.go
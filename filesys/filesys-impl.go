@@ -0,0 +1,254 @@
+package filesys
+
+import (
+	"errors"
+
+	"github.com/yashsriv/go-nachos/disk"
+	"github.com/yashsriv/go-nachos/interfaces"
+	"github.com/yashsriv/go-nachos/threads/synch"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// freeMapFileSize is the number of bytes the bitmap of free sectors takes
+// in its file, one bit per disk sector
+const freeMapFileSize = (disk.NumSectors + 7) / 8
+
+// Init initializes the file system on the disk simulated by the UNIX file
+// "name".  If "format" is true, the disk has nothing on it, and we need
+// to initialize it to contain an empty directory, and a bitmap of free
+// sectors (with almost but not all of the sectors marked as free).
+//
+// If "format" is false, we just have to open the files representing the
+// bitmap and the directory.
+func (fs *FileSystem) Init(name string, format bool) {
+	utils.Debug('f', "Initializing the file system.\n")
+	fs.disk = &disk.SynchDisk{}
+	fs.disk.Init(name)
+	fs.lock = &synch.RWLock{}
+	fs.lock.Init("file system lock", true)
+	fs.openLock = &synch.Lock{}
+	fs.openLock.Init("open files lock")
+	fs.openHeaders = make(map[int]*openHeader)
+
+	if format {
+		utils.Debug('f', "Formatting the file system.\n")
+		freeMap := &utils.BitMap{}
+		freeMap.Init(disk.NumSectors)
+		directory := &Directory{}
+		directory.Init(NumDirEntries)
+		mapHdr := &FileHeader{}
+		dirHdr := &FileHeader{}
+
+		// First, allocate space for FileHeaders for the directory and bitmap
+		// (make sure no one else grabs these!)
+		freeMap.Mark(FreeMapSector)
+		freeMap.Mark(DirectorySector)
+
+		// Second, allocate space for the data blocks containing the contents
+		// of the directory and bitmap files.  There better be enough space!
+		utils.Assert(mapHdr.Allocate(fs.disk, freeMap, freeMapFileSize), "There should be space for the bitmap")
+		utils.Assert(dirHdr.Allocate(fs.disk, freeMap, directoryFileSize), "There should be space for the directory")
+
+		// Flush the bitmap and directory FileHeaders back to disk.  We need
+		// to do this before we can "Open" the file, since open reads the
+		// file header off of disk (and currently the disk has garbage on
+		// it!).
+		mapHdr.WriteBack(fs.disk, FreeMapSector)
+		dirHdr.WriteBack(fs.disk, DirectorySector)
+
+		// OK to open the bitmap and directory files now.  The file system
+		// operations assume these two files are left open while Nachos is
+		// running.
+		fs.freeMapFile = fs.openSector(FreeMapSector)
+		fs.directoryFile = fs.openSector(DirectorySector)
+
+		// Once we have the files "open", we can write the initial version of
+		// each file back to disk.  The directory at this point is completely
+		// empty; but the bitmap has been changed to reflect the fact that
+		// sectors on the disk have been allocated for the file headers and
+		// to hold the file data for the directory and bitmap.
+		fs.freeMapFile.WriteAt(freeMap.WriteBack(), 0)
+		directory.WriteBack(fs.directoryFile)
+		return
+	}
+
+	// If we are not formatting the disk, just open the files representing
+	// the bitmap and directory; these are left open while Nachos is
+	// running.
+	fs.freeMapFile = fs.openSector(FreeMapSector)
+	fs.directoryFile = fs.openSector(DirectorySector)
+	if fs.freeMapFile.Length() != freeMapFileSize || fs.directoryFile.Length() != directoryFileSize {
+		utils.Panic(errors.New("The disk holds no file system, format it with -f"))
+	}
+}
+
+// fetchFreeMap reads the bitmap of free sectors from its file.
+// Must be called with the file system lock held.
+func (fs *FileSystem) fetchFreeMap() *utils.BitMap {
+	freeMap := &utils.BitMap{}
+	freeMap.Init(disk.NumSectors)
+	data := make([]byte, freeMapFileSize)
+	fs.freeMapFile.ReadAt(data, 0)
+	freeMap.FetchFrom(data)
+	return freeMap
+}
+
+// fetchDirectory reads the directory from its file.
+// Must be called with the file system lock held.
+func (fs *FileSystem) fetchDirectory() *Directory {
+	directory := &Directory{}
+	directory.Init(NumDirEntries)
+	directory.FetchFrom(fs.directoryFile)
+	return directory
+}
+
+// Create creates a file in the Nachos file system, with "initialSize"
+// bytes.
+//
+// The steps to create a file are:
+//
+//	Make sure the file doesn't already exist
+//	Allocate a sector for the file header
+//	Allocate space on disk for the data blocks for the file
+//	Add the name to the directory
+//	Store the new file header on disk
+//	Flush the changes to the bitmap and the directory back to disk
+//
+// Returns false if the file already exists, its name is not valid, or
+// there is no space for it -- either in the directory, or on disk.
+func (fs *FileSystem) Create(name string, initialSize int) bool {
+	utils.Debug('f', "Creating file %s, size %d\n", name, initialSize)
+	fs.lock.AcquireWrite()
+	defer fs.lock.ReleaseWrite()
+
+	directory := fs.fetchDirectory()
+	if directory.Find(name) != -1 {
+		return false // file is already in directory
+	}
+	freeMap := fs.fetchFreeMap()
+	sector := freeMap.Find() // find a sector to hold the file header
+	if sector == -1 {
+		return false // no free block for file header
+	}
+	if !directory.Add(name, sector) {
+		return false // no space in directory, or bad name
+	}
+	hdr := &FileHeader{}
+	if !hdr.Allocate(fs.disk, freeMap, initialSize) {
+		return false // no space on disk for data
+	}
+	// everything worked, flush all changes back to disk
+	hdr.WriteBack(fs.disk, sector)
+	directory.WriteBack(fs.directoryFile)
+	fs.freeMapFile.WriteAt(freeMap.WriteBack(), 0)
+	return true
+}
+
+// Open opens a file for reading and writing.
+//
+// Returns nil if the file is not in the directory.
+func (fs *FileSystem) Open(name string) interfaces.IOpenFile {
+	utils.Debug('f', "Opening file %s\n", name)
+	fs.lock.AcquireRead()
+	defer fs.lock.ReleaseRead()
+
+	sector := fs.fetchDirectory().Find(name)
+	if sector == -1 {
+		return nil // name was not found in directory
+	}
+	return fs.openSector(sector)
+}
+
+// openSector opens the file whose header is in "sector".  Everybody
+// who opens the same file shares its header, so that they all see the
+// file grow.
+func (fs *FileSystem) openSector(sector int) *OpenFile {
+	fs.openLock.Acquire()
+	defer fs.openLock.Release()
+
+	open, ok := fs.openHeaders[sector]
+	if !ok {
+		open = &openHeader{hdr: &FileHeader{}}
+		open.hdr.FetchFrom(fs.disk, sector)
+		fs.openHeaders[sector] = open
+	}
+	open.count++
+	return &OpenFile{fs: fs, sector: sector, hdr: open.hdr}
+}
+
+// closeHeader lets go of the header in "sector", which an OpenFile
+// no longer uses.
+func (fs *FileSystem) closeHeader(sector int) {
+	fs.openLock.Acquire()
+	defer fs.openLock.Release()
+
+	open := fs.openHeaders[sector]
+	utils.Assert(open != nil && open.count > 0, "Only an open file can be closed")
+	open.count--
+	if open.count == 0 {
+		delete(fs.openHeaders, sector)
+	}
+}
+
+// extend grows the file open as "of" to "fileSize" bytes.
+//
+// Returns false if there is no space for it.
+func (fs *FileSystem) extend(of *OpenFile, fileSize int) bool {
+	fs.lock.AcquireWrite()
+	defer fs.lock.ReleaseWrite()
+
+	freeMap := fs.fetchFreeMap()
+	if !of.hdr.Extend(fs.disk, freeMap, fileSize) {
+		return false
+	}
+	utils.Debug('f', "Extended the file in sector %d to %d bytes\n", of.sector, fileSize)
+	of.hdr.WriteBack(fs.disk, of.sector)
+	fs.freeMapFile.WriteAt(freeMap.WriteBack(), 0)
+	return true
+}
+
+// Remove deletes a file from the file system.  This requires:
+//
+//	Remove it from the directory
+//	Delete the space for its header
+//	Delete the space for its data blocks
+//	Write changes to directory, bitmap back to disk
+//
+// Returns false if the file is not in the file system, or somebody has it
+// open.
+func (fs *FileSystem) Remove(name string) bool {
+	utils.Debug('f', "Removing file %s\n", name)
+	fs.lock.AcquireWrite()
+	defer fs.lock.ReleaseWrite()
+
+	directory := fs.fetchDirectory()
+	sector := directory.Find(name)
+	if sector == -1 {
+		return false // file not found
+	}
+	fs.openLock.Acquire()
+	_, open := fs.openHeaders[sector]
+	fs.openLock.Release()
+	if open {
+		return false
+	}
+
+	hdr := &FileHeader{}
+	hdr.FetchFrom(fs.disk, sector)
+	freeMap := fs.fetchFreeMap()
+	hdr.Deallocate(freeMap) // remove data blocks
+	freeMap.Clear(sector)   // remove header block
+	directory.Remove(name)
+
+	fs.freeMapFile.WriteAt(freeMap.WriteBack(), 0) // flush to disk
+	directory.WriteBack(fs.directoryFile)          // flush to disk
+	return true
+}
+
+// List lists all the files in the file system directory.
+func (fs *FileSystem) List() {
+	fs.lock.AcquireRead()
+	defer fs.lock.ReleaseRead()
+
+	fs.fetchDirectory().List()
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package filesys
+
+import "github.com/yashsriv/go-nachos/interfaces"
+
+// Sectors containing the file headers for the bitmap of free sectors,
+// and the directory of files.  These file headers are placed in
+// well-known sectors, so that they can be located on boot-up.
+const (
+	FreeMapSector   = 0
+	DirectorySector = 1
+)
+
+// FileSystem is the data structure representing a Nachos file system.
+//
+// The file system consists of several data structures:
+//
+//	A bitmap of free disk sectors
+//
+//	A directory of file names and file headers
+//
+// Both the bitmap and the directory are represented as normal files.
+// Their file headers are located in specific sectors (sector 0 and
+// sector 1), so that the file system can find them on bootup.
+//
+// The file system assumes that the bitmap and directory files are kept
+// "open" continuously while Nachos is running.  They are read from disk
+// for every operation which uses them, and written back when modified.
+//
+// The directory is looked at far more often than it is changed, so it
+// is protected by a reader-writer lock.
+type FileSystem struct {
+	disk interfaces.ISynchDisk
+
+	freeMapFile   *OpenFile // Bit map of free disk blocks, represented as a file
+	directoryFile *OpenFile // "Root" directory -- list of file names, represented as a file
+
+	lock        interfaces.IRWLock  // protects the bitmap and the directory
+	openLock    interfaces.ILock    // protects openHeaders
+	openHeaders map[int]*openHeader // headers of the open files, by sector
+}
+
+// openHeader is the header of a file which somebody has open
+type openHeader struct {
+	hdr   *FileHeader
+	count int // number of OpenFiles using it
+}
+
+var _ interfaces.IFileSystem = &FileSystem{}
+
+// Implemented in filesys-impl.go
@@ -0,0 +1,101 @@
+package filesys
+
+import (
+	"github.com/yashsriv/go-nachos/disk"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// ReadAt reads a portion of the file, starting at "position".
+//
+// Returns the number of bytes actually read.
+//
+// There is no guarantee the request starts or ends on an even disk
+// sector boundary; however the disk only knows how to read a whole
+// sector at a time.  Thus every sector overlapping the request is read
+// into a buffer, and the part asked for is copied out of it.
+func (of *OpenFile) ReadAt(into []byte, position int) int {
+	fileLength := of.hdr.FileLength()
+	numBytes := len(into)
+	if numBytes <= 0 || position < 0 || position >= fileLength {
+		return 0 // check request
+	}
+	if position+numBytes > fileLength {
+		numBytes = fileLength - position
+	}
+	utils.Debug('f', "Reading %d bytes at %d, from file of length %d.\n", numBytes, position, fileLength)
+
+	firstSector := position / disk.SectorSize
+	lastSector := (position + numBytes - 1) / disk.SectorSize
+	buf := make([]byte, (lastSector-firstSector+1)*disk.SectorSize)
+	for i := firstSector; i <= lastSector; i++ {
+		of.fs.disk.ReadSector(of.hdr.ByteToSector(i*disk.SectorSize),
+			buf[(i-firstSector)*disk.SectorSize:(i-firstSector+1)*disk.SectorSize])
+	}
+
+	start := position - firstSector*disk.SectorSize
+	copy(into, buf[start:start+numBytes])
+	return numBytes
+}
+
+// WriteAt writes a portion of the file, starting at "position", growing
+// the file first if the write goes past its end.
+//
+// Returns the number of bytes actually written.
+//
+// As with ReadAt, the disk only writes whole sectors, so the first and
+// last sectors are read in first if the request only covers part of them.
+func (of *OpenFile) WriteAt(from []byte, position int) int {
+	numBytes := len(from)
+	if numBytes <= 0 || position < 0 || position > MaxFileSize {
+		return 0 // check request
+	}
+	if position+numBytes > MaxFileSize {
+		numBytes = MaxFileSize - position
+	}
+	if position+numBytes > of.hdr.FileLength() && !of.fs.extend(of, position+numBytes) {
+		// Only write what fits in the file as it is
+		numBytes = of.hdr.FileLength() - position
+		if numBytes <= 0 {
+			return 0
+		}
+	}
+	utils.Debug('f', "Writing %d bytes at %d, to file of length %d.\n", numBytes, position, of.hdr.FileLength())
+
+	firstSector := position / disk.SectorSize
+	lastSector := (position + numBytes - 1) / disk.SectorSize
+	buf := make([]byte, (lastSector-firstSector+1)*disk.SectorSize)
+
+	start := position - firstSector*disk.SectorSize
+	if start != 0 { // first sector is only partially written
+		of.ReadAt(buf[:disk.SectorSize], firstSector*disk.SectorSize)
+	}
+	if end := start + numBytes; end%disk.SectorSize != 0 &&
+		(lastSector != firstSector || start == 0) { // last sector is only partially written
+		of.ReadAt(buf[len(buf)-disk.SectorSize:], lastSector*disk.SectorSize)
+	}
+	copy(buf[start:], from[:numBytes])
+
+	for i := firstSector; i <= lastSector; i++ {
+		of.fs.disk.WriteSector(of.hdr.ByteToSector(i*disk.SectorSize),
+			buf[(i-firstSector)*disk.SectorSize:(i-firstSector+1)*disk.SectorSize])
+	}
+	return numBytes
+}
+
+// Length returns the number of bytes in the file.
+func (of *OpenFile) Length() int {
+	return of.hdr.FileLength()
+}
+
+// NumSectors returns the number of disk sectors holding the data of the
+// file.
+func (of *OpenFile) NumSectors() int {
+	return int(of.hdr.NumSectors)
+}
+
+// Close is called when we are done with the file.  The header stays in
+// memory while somebody else has the file open.
+func (of *OpenFile) Close() {
+	of.fs.closeHeader(of.sector)
+	of.hdr = nil
+}
@@ -0,0 +1,21 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package filesys
+
+import "github.com/yashsriv/go-nachos/interfaces"
+
+// OpenFile is a file which has been opened for reading and writing.
+//
+// Reads and writes go through the disk one whole sector at a time, so
+// they are charged the simulated disk latency.  Writing past the end of
+// the file makes it grow.
+type OpenFile struct {
+	fs     *FileSystem
+	sector int         // disk sector of the file header
+	hdr    *FileHeader // header of this file, shared by everybody who opened it
+}
+
+var _ interfaces.IOpenFile = &OpenFile{}
+
+// Implemented in openfile-impl.go
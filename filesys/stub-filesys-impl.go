@@ -0,0 +1,161 @@
+package filesys
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yashsriv/go-nachos/disk"
+	"github.com/yashsriv/go-nachos/interfaces"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// Init sets up the stub file system to keep its files in the UNIX
+// directory "root", which is created if need be.
+func (fs *StubFileSystem) Init(root string) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		utils.Panic(err)
+	}
+	// Keep the real location, to check where file names lead
+	root, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		utils.Panic(err)
+	}
+	fs.root = root
+}
+
+// path returns the UNIX path of the Nachos file "name".
+//
+// Returns false if "name" would lead out of the sandbox.
+func (fs *StubFileSystem) path(name string) (string, bool) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\x00") {
+		utils.Debug('f', "Bad file name %q\n", name)
+		return "", false
+	}
+	path := filepath.Join(fs.root, name)
+	// The file itself may be a symbolic link out of the sandbox
+	real, err := filepath.EvalSymlinks(path)
+	if os.IsNotExist(err) {
+		return path, true // nothing there yet, or a dangling link
+	}
+	if err != nil {
+		utils.Debug('f', "Bad file name %q: %v\n", name, err)
+		return "", false
+	}
+	if rel, err := filepath.Rel(fs.root, real); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		utils.Debug('f', "File name %q leads to %s, out of the sandbox\n", name, real)
+		return "", false
+	}
+	return path, true
+}
+
+// Create creates a UNIX file with "initialSize" bytes, all zero.
+//
+// Returns false if the file already exists, or can't be created.
+func (fs *StubFileSystem) Create(name string, initialSize int) bool {
+	utils.Debug('f', "Creating file %s, size %d\n", name, initialSize)
+	path, ok := fs.path(name)
+	if !ok {
+		return false
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		utils.Debug('f', "Create failed: %v\n", err)
+		return false
+	}
+	defer file.Close()
+	if err := file.Truncate(int64(initialSize)); err != nil {
+		utils.Debug('f', "Create failed: %v\n", err)
+		return false
+	}
+	return true
+}
+
+// Open opens a UNIX file for reading and writing.
+//
+// Returns nil if there is no such file.
+func (fs *StubFileSystem) Open(name string) interfaces.IOpenFile {
+	utils.Debug('f', "Opening file %s\n", name)
+	path, ok := fs.path(name)
+	if !ok {
+		return nil
+	}
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		utils.Debug('f', "Open failed: %v\n", err)
+		return nil
+	}
+	return &StubOpenFile{file: file}
+}
+
+// Remove deletes a UNIX file.
+//
+// Returns false if there is no such file.
+func (fs *StubFileSystem) Remove(name string) bool {
+	utils.Debug('f', "Removing file %s\n", name)
+	path, ok := fs.path(name)
+	if !ok {
+		return false
+	}
+	return os.Remove(path) == nil
+}
+
+// List lists all the files in the sandbox directory.
+func (fs *StubFileSystem) List() {
+	entries, err := os.ReadDir(fs.root)
+	if err != nil {
+		utils.Panic(err)
+	}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			fmt.Printf("%s\n", entry.Name())
+		}
+	}
+}
+
+// ReadAt reads from the file, starting at "position".
+//
+// Returns the number of bytes actually read -- fewer than asked for at
+// the end of the file.
+func (of *StubOpenFile) ReadAt(into []byte, position int) int {
+	if position < 0 {
+		return 0
+	}
+	n, _ := of.file.ReadAt(into, int64(position)) // a short read is fine
+	return n
+}
+
+// WriteAt writes to the file, starting at "position".
+//
+// Returns the number of bytes actually written.
+func (of *StubOpenFile) WriteAt(from []byte, position int) int {
+	if position < 0 {
+		return 0
+	}
+	n, err := of.file.WriteAt(from, int64(position))
+	if err != nil {
+		utils.Debug('f', "Write failed: %v\n", err)
+	}
+	return n
+}
+
+// Length returns the number of bytes in the file.
+func (of *StubOpenFile) Length() int {
+	info, err := of.file.Stat()
+	if err != nil {
+		utils.Panic(err)
+	}
+	return int(info.Size())
+}
+
+// NumSectors returns the number of sectors the file would take on the
+// simulated disk.
+func (of *StubOpenFile) NumSectors() int {
+	return divRoundUp(of.Length(), disk.SectorSize)
+}
+
+// Close closes the UNIX file.
+func (of *StubOpenFile) Close() {
+	of.file.Close()
+}
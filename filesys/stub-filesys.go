@@ -0,0 +1,31 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package filesys
+
+import (
+	"os"
+
+	"github.com/yashsriv/go-nachos/interfaces"
+)
+
+// StubFileSystem is a "stub" file system, to be used until the Nachos
+// file system on the simulated disk is ready.  Nachos files are simply
+// UNIX files, all of them in one sandbox directory on the host.
+//
+// A file name may not name anything outside of the sandbox -- it may not
+// contain a '/', may not be "." or "..", and may not be a symbolic link
+// to a file elsewhere.
+type StubFileSystem struct {
+	root string // the sandbox directory
+}
+
+// StubOpenFile is a UNIX file open through the stub file system
+type StubOpenFile struct {
+	file *os.File
+}
+
+var _ interfaces.IFileSystem = &StubFileSystem{}
+var _ interfaces.IOpenFile = &StubOpenFile{}
+
+// Implemented in stub-filesys-impl.go
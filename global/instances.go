@@ -9,12 +9,9 @@ import (
 	"github.com/yashsriv/go-nachos/utils"
 )
 
-// MaxProcesses that can be spawned
+// MaxProcesses that can be alive at the same time
 const MaxProcesses = 1024
 
-// ControlChannel are channels for synchronization of threads
-var ControlChannel [MaxProcesses]chan int
-
 // Following are *all* the global instances of interfaces required by
 // NachOS
 
@@ -45,3 +42,9 @@ var ExceptionHandler func(which enums.ExceptionType)
 
 // Console is an instance of console
 var Console interfaces.IConsole
+
+// SynchConsole is the console shared by all user programs
+var SynchConsole interfaces.ISynchConsole
+
+// FileSystem is the file system user programs keep their files in
+var FileSystem interfaces.IFileSystem
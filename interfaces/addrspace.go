@@ -5,7 +5,11 @@ package interfaces
 
 // IProcessAddressSpace defines the interface for an address space
 type IProcessAddressSpace interface {
-	Init(string)
+	Init(string) error
+	InitFrom(IProcessAddressSpace) error
+	Release()
+	HandlePageFault(uint32) bool
+	HandleCopyOnWrite(uint32) bool
 	InitUserModeCPURegisters()
 	RestoreContextOnSwitch()
 	SaveContextOnSwitch()
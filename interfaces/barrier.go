@@ -0,0 +1,14 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package interfaces
+
+// IBarrier defines the interface for a barrier
+type IBarrier interface {
+	Init(string, int)
+	Name() string
+
+	Wait()
+}
+
+// Concrete implementation in threads/synch/barrier.go
@@ -0,0 +1,16 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package interfaces
+
+// ICondition defines the interface for a condition variable
+type ICondition interface {
+	Init(string)
+	Name() string
+
+	Wait(ILock)
+	Signal(ILock)
+	Broadcast(ILock)
+}
+
+// Concrete implementation in threads/synch/condition.go
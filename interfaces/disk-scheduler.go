@@ -0,0 +1,24 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package interfaces
+
+// IDiskRequest is a request waiting for the disk.  Disk schedulers only
+// need to know which sector it is for.
+type IDiskRequest interface {
+	Sector() int
+}
+
+// IDiskScheduler defines the interface for a disk head scheduling policy.
+// Requests which arrive while the disk is busy wait with the scheduler,
+// which decides which of them the disk serves next.
+type IDiskScheduler interface {
+	Init()
+	Name() string
+
+	Add(request IDiskRequest)   // "request" has to wait for the disk
+	Next(head int) IDiskRequest // Choose and remove the next request, the head being over sector "head"
+	Len() int                   // Number of requests waiting
+}
+
+// Concrete implementations in disk/disk-scheduler.go
@@ -0,0 +1,26 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package interfaces
+
+// IFileSystem defines the interface for a file system, which names files
+// and finds them.  How it is set up depends on where the files are kept,
+// so initialization is left to the concrete implementation.
+type IFileSystem interface {
+	Create(name string, initialSize int) bool // Create a file, false if it can't be done
+	Open(name string) IOpenFile               // Open a file, nil if there is no such file
+	Remove(name string) bool                  // Delete a file, false if it can't be done
+	List()                                    // Print the names of all the files
+}
+
+// IOpenFile defines the interface for a file which is open for reading
+// and writing
+type IOpenFile interface {
+	ReadAt([]byte, int) int  // Read at the given position
+	WriteAt([]byte, int) int // Write at the given position
+	Length() int             // Number of bytes in the file
+	NumSectors() int         // Number of disk sectors holding the file
+	Close()                  // Done with the file
+}
+
+// Concrete implementation in filesys/filesys.go
@@ -0,0 +1,16 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package interfaces
+
+// ILock defines the interface for a lock
+type ILock interface {
+	Init(string)
+	Name() string
+
+	Acquire()
+	Release()
+	IsHeldByCurrentThread() bool
+}
+
+// Concrete implementation in threads/synch/lock.go
@@ -24,6 +24,8 @@ type IMachine interface {
 	Translate(virtAddr uint32, size int, writing bool) (uint32, enums.ExceptionType)
 	RaiseException(which enums.ExceptionType, badVAddr uint32)
 
+	EnableTLB(size int)
+	TLB() []utils.TranslationEntry
 	PageTable() []utils.TranslationEntry
 	SetPageTable([]utils.TranslationEntry)
 	GetMainMemory() []byte
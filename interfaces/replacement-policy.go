@@ -0,0 +1,19 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package interfaces
+
+// IReplacementPolicy defines the interface for a page replacement policy.
+// When physical memory runs out, the policy decides which frame gets its
+// page evicted to make room for the faulting one.
+type IReplacementPolicy interface {
+	Init(numFrames uint32)
+	Name() string
+
+	FrameLoaded(frame uint32) // A page has just been brought into "frame"
+	FrameFreed(frame uint32)  // "frame" no longer holds a page
+	SelectVictim() uint32     // Choose a frame in use to evict
+	SampleUse()               // Called on every timer interrupt, to look at the use bits
+}
+
+// Concrete implementations in userprog/replacement.go
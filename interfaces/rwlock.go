@@ -0,0 +1,17 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package interfaces
+
+// IRWLock defines the interface for a reader-writer lock
+type IRWLock interface {
+	Init(string, bool)
+	Name() string
+
+	AcquireRead()
+	ReleaseRead()
+	AcquireWrite()
+	ReleaseWrite()
+}
+
+// Concrete implementation in threads/synch/rwlock.go
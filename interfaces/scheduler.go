@@ -9,6 +9,9 @@ type IScheduler interface {
 	MoveThreadToReadyQueue(IThread)
 	SelectNextReadyThread() IThread
 	ScheduleThread(IThread)
+	MoveThreadToSleepQueue(IThread, int)
+	WakeSleepingThreads()
+	HasSleepingThreads() bool
 	Print()
 }
 
@@ -0,0 +1,16 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package interfaces
+
+// ISynchConsole defines the interface for a console device which can be
+// shared by all threads
+type ISynchConsole interface {
+	Init(string, string)
+	PutChar(byte)
+	GetChar() (byte, error)
+
+	Close()
+}
+
+// Concrete implementation in console/synch-console.go
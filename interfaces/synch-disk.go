@@ -0,0 +1,16 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package interfaces
+
+// ISynchDisk defines the interface for a disk which can be shared by all
+// threads
+type ISynchDisk interface {
+	Init(string)
+	Close()
+
+	ReadSector(int, []byte)
+	WriteSector(int, []byte)
+}
+
+// Concrete implementation in disk/synch-disk.go
@@ -15,6 +15,7 @@ type IThread interface {
 	Init(string)
 	ThreadFork(utils.VoidFunction, interface{})
 	YieldCPU()
+	Sleep(int)
 	PutThreadToSleep()
 	FinishThread()
 	SetStatus(enums.ThreadStatus)
@@ -22,10 +23,13 @@ type IThread interface {
 
 	SaveUserState()
 	RestoreUserState()
+	ResetReturnValue()
 	Space() IProcessAddressSpace
 	SetSpace(IProcessAddressSpace)
 	PID() int
 	PPID() int
+	NumInstr() int
+	IncrementNumInstr()
 }
 
 // Concrete implementation in threads/thread.go
@@ -233,8 +233,10 @@ func (interrupt *Interrupt) checkIfDue(advanceClock bool) bool {
 		return false
 	}
 
-	// Check if there is nothing more to do, and if so, quit
-	if (interrupt.status == enums.IdleMode) && (toOccur.TypeInt == enums.TimerInt) && interrupt.pending.Len() == 0 {
+	// Check if there is nothing more to do, and if so, quit.  Sleeping
+	// threads are woken up by the timer, so keep it going for them.
+	if (interrupt.status == enums.IdleMode) && (toOccur.TypeInt == enums.TimerInt) && interrupt.pending.Len() == 0 &&
+		!global.Scheduler.HasSleepingThreads() {
 		interrupt.pending.PushFront(toOccur)
 		return false
 	}
@@ -111,6 +111,22 @@ func (m *Machine) WriteRegister(num int, value uint32) {
 	m.Registers[num] = value
 }
 
+// EnableTLB gives the machine a TLB with "size" entries, all of them
+// invalid to begin with.
+//
+// Once there is a TLB, every translation goes through it.  The page table
+// is only for the kernel to refill the TLB from, on a TLB miss.
+func (m *Machine) EnableTLB(size int) {
+	utils.Assert(size > 0, "The TLB should have at least one entry")
+	m.tlb = make([]utils.TranslationEntry, size)
+}
+
+// TLB allows getting the entries of the TLB, so that the kernel can
+// manage them.  Returns nil if there is no TLB.
+func (m *Machine) TLB() []utils.TranslationEntry {
+	return m.tlb
+}
+
 // PageTable allows getting the page table
 func (m *Machine) PageTable() []utils.TranslationEntry {
 	return m.KernelPageTable
@@ -45,6 +45,7 @@ type Machine struct {
 	Registers  [NumTotalRegs]uint32
 
 	KernelPageTable []utils.TranslationEntry
+	tlb             []utils.TranslationEntry // nil unless a TLB is enabled
 
 	singleStep   bool
 	runUntilTime int
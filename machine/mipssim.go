@@ -9,7 +9,6 @@ package machine
 
 import (
 	"fmt"
-	"runtime"
 
 	"github.com/yashsriv/go-nachos/enums"
 	"github.com/yashsriv/go-nachos/global"
@@ -785,37 +784,13 @@ func (m *Machine) Run() {
 		fmt.Printf("Starting thread %q at time %d\n", global.CurrentThread, global.Stats.TotalTicks)
 	}
 
-	pid := global.CurrentThread.PID()
-	global.ControlChannel[pid] = make(chan int, 1)
-	ws := global.ControlChannel[pid]
-	state := 1 // Running
 	global.Interrupt.SetStatus(enums.UserMode)
 	for {
-		select {
-		case state = <-ws:
-			switch state {
-			case 1:
-				utils.Debug('t', "Thread %d: Running", pid)
-			case 0:
-				utils.Debug('t', "Thread %d: Paused", pid)
-			}
-
-		default:
-			// We use runtime.Gosched() to prevent a deadlock in this case.
-			// It will not be needed of work is performed here which yields
-			// to the scheduler.
-			runtime.Gosched()
-
-			if state == 0 {
-				break
-			}
-
-			m.OneInstruction(instr)
-			global.Interrupt.OneTick()
-			if m.singleStep && (m.runUntilTime <= global.Stats.TotalTicks) {
-				m.Debugger()
-			}
-			// Do actual work here.
+		m.OneInstruction(instr)
+		global.CurrentThread.IncrementNumInstr()
+		global.Interrupt.OneTick()
+		if m.singleStep && (m.runUntilTime <= global.Stats.TotalTicks) {
+			m.Debugger()
 		}
 	}
 }
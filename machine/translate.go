@@ -110,28 +110,49 @@ func (m *Machine) Translate(virtAddr uint32, size int, writing bool) (physAddr u
 		return
 	}
 
-	// TODO: When I add a TLB if I add one
-	// we must have either a TLB or a page table, but not both!
-	// ASSERT(tlb == NULL || KernelPageTable == NULL);
-	utils.Assert(m.KernelPageTable != nil, "KernelPageTable should not be nil")
-
 	// calculate the virtual page number, and offset within the page,
 	// from the virtual address
 	vpn := virtAddr / PageSize
 	offset := virtAddr % PageSize
 
+	// The page table tells how big the address space is, with or without
+	// a TLB
+	utils.Assert(m.KernelPageTable != nil, "KernelPageTable should not be nil")
 	if vpn >= uint32(len(m.KernelPageTable)) {
 		utils.Debug('a', "virtual page # %d too large for page table size %d!\n",
 			virtAddr, len(m.KernelPageTable))
 		exception = enums.AddressErrorException
 		return
-	} else if !m.KernelPageTable[vpn].Valid {
-		utils.Debug('a', "virtual page # %d missing!\n",
-			vpn)
-		exception = enums.PageFaultException
-		return
 	}
-	entry := &m.KernelPageTable[vpn]
+
+	var entry *utils.TranslationEntry
+	if m.tlb == nil {
+		if !m.KernelPageTable[vpn].Valid {
+			utils.Debug('a', "virtual page # %d missing!\n",
+				vpn)
+			exception = enums.PageFaultException
+			return
+		}
+		entry = &m.KernelPageTable[vpn]
+	} else {
+		// The page table entries are not looked at; a miss is left to the kernel,
+		// which loads the missing translation into the TLB.
+		for i := range m.tlb {
+			if m.tlb[i].Valid && m.tlb[i].VirtualPage == vpn {
+				entry = &m.tlb[i] // FOUND!
+				break
+			}
+		}
+		if entry == nil { // not found
+			utils.Debug('a', "*** no valid TLB entry found for virtual page # %d!\n", vpn)
+			global.Stats.NumTLBMisses++
+			exception = enums.PageFaultException // really, this is a TLB fault,
+			// the page may be in memory,
+			// but not in the TLB
+			return
+		}
+		global.Stats.NumTLBHits++
+	}
 
 	if entry.ReadOnly && writing { // trying to write to a read-only page
 		utils.Debug('a', "%d mapped read-only at %d in page table!\n", virtAddr, entry.PhysicalPage)
@@ -4,11 +4,16 @@
 package main
 
 import (
+	"errors"
 	"flag"
+	"fmt"
 	"os"
 	"os/signal"
 
+	"github.com/yashsriv/go-nachos/console"
+	"github.com/yashsriv/go-nachos/disk"
 	"github.com/yashsriv/go-nachos/enums"
+	"github.com/yashsriv/go-nachos/filesys"
 	"github.com/yashsriv/go-nachos/global"
 	"github.com/yashsriv/go-nachos/machine"
 	"github.com/yashsriv/go-nachos/threads"
@@ -22,7 +27,17 @@ func initialize() {
 	var seed utils.Int64Flag
 	flag.Var(&debugArgs, "d", "set debug flags")
 	flag.Var(&seed, "rs", "seed random number generator")
+	var replacementPolicy utils.StringFlag
+	flag.Var(&replacementPolicy, "rp", "page replacement policy: fifo, random, lru or clock")
+	var diskPolicy utils.StringFlag
+	flag.Var(&diskPolicy, "ds", "disk scheduling policy: fcfs, sstf, scan or cscan")
 	var singleStep = flag.Bool("s", false, "debug the user program step by step")
+	var demandPaging = flag.Bool("dp", false, "load pages of user programs on demand")
+	var tlbSize = flag.Int("tlb", 0, "number of TLB entries, translate through the page table if 0")
+	var fileSystemType = flag.String("fs", "stub", "file system: stub (UNIX files) or disk (on the simulated disk)")
+	var sandbox = flag.String("fsroot", "nachos-fs", "UNIX directory holding the files of the stub file system")
+	var format = flag.Bool("f", false, "format the disk of the file system")
+	var listFiles = flag.Bool("l", false, "list the files in the file system")
 
 	flag.Parse()
 
@@ -49,17 +64,36 @@ func initialize() {
 
 	global.Timer = &machine.Timer{}
 	global.Timer.Init(func(arg interface{}) {
+		global.Scheduler.WakeSleepingThreads()
+		userprog.SampleFrameUse()
 		if global.Interrupt.GetStatus() != enums.IdleMode {
 			global.Interrupt.YieldOnReturn()
 		}
 	}, nil, randomYield)
 
+	if diskPolicy.IsSet {
+		if err := disk.SetSchedulingPolicy(diskPolicy.Value); err != nil {
+			utils.Panic(err)
+		}
+	}
+
 	userprog.Init()
+	if *demandPaging {
+		userprog.EnableDemandPaging("SWAP")
+	}
+	if replacementPolicy.IsSet {
+		if err := userprog.SetReplacementPolicy(replacementPolicy.Value); err != nil {
+			utils.Panic(err)
+		}
+	}
 
 	global.Machine = &machine.Machine{}
 	if *singleStep {
 		global.Machine.EnableDebugging()
 	}
+	if *tlbSize > 0 {
+		global.Machine.EnableTLB(*tlbSize)
+	}
 
 	// We didn't explicitly allocate the current thread we are running in.
 	// But if it ever tries to yield, we better have a thread object to save
@@ -70,6 +104,27 @@ func initialize() {
 
 	global.Interrupt.Enable()
 
+	// The file system on the disk does disk I/O, so it can only be set up
+	// once there is a thread to wait for the disk.
+	switch *fileSystemType {
+	case "stub":
+		if *format {
+			utils.Panic(errors.New("Only the disk file system can be formatted, use -fs disk"))
+		}
+		fileSystem := &filesys.StubFileSystem{}
+		fileSystem.Init(*sandbox)
+		global.FileSystem = fileSystem
+	case "disk":
+		fileSystem := &filesys.FileSystem{}
+		fileSystem.Init("DISK", *format)
+		global.FileSystem = fileSystem
+	default:
+		utils.Panic(fmt.Errorf("Unknown file system %q", *fileSystemType))
+	}
+	if *listFiles {
+		global.FileSystem.List()
+	}
+
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
 	go func() {
@@ -85,6 +140,10 @@ func main() {
 	flag.Var(&program, "x", "runs a user program")
 	initialize()
 	if program.IsSet {
+		// The console always has interrupts pending, so the machine never
+		// idles to a halt once it is started; the last process halts it.
+		global.SynchConsole = &console.SynchConsole{}
+		global.SynchConsole.Init("", "")
 		userprog.LaunchUserProcess(program.Value)
 	}
 	global.CurrentThread.FinishThread()
@@ -218,6 +218,30 @@ syscall_wrapper_PrintIntHex:
 	j	$31
 	.end syscall_wrapper_PrintIntHex
 
+	.globl syscall_wrapper_Dup
+	.ent    syscall_wrapper_Dup
+syscall_wrapper_Dup:
+	addiu $2,$0,SysCall_Dup
+	syscall
+	j	$31
+	.end syscall_wrapper_Dup
+
+	.globl syscall_wrapper_Seek
+	.ent    syscall_wrapper_Seek
+syscall_wrapper_Seek:
+	addiu $2,$0,SysCall_Seek
+	syscall
+	j	$31
+	.end syscall_wrapper_Seek
+
+	.globl syscall_wrapper_Stat
+	.ent    syscall_wrapper_Stat
+syscall_wrapper_Stat:
+	addiu $2,$0,SysCall_Stat
+	syscall
+	j	$31
+	.end syscall_wrapper_Stat
+
 /* dummy function to keep gcc happy */
         .globl  __main
         .ent    __main
@@ -16,6 +16,8 @@ import (
 // Init initialises the data structures of this scheduler
 func (s *Scheduler) Init() {
 	s.listOfReadyThreads = list.New()
+	s.listOfSleepingThreads = list.New()
+	threadRegistry.Init()
 }
 
 // MoveThreadToReadyQueue marks a thread as ready, but not running.
@@ -33,6 +35,50 @@ func (s *Scheduler) MoveThreadToReadyQueue(thread interfaces.IThread) {
 	}
 }
 
+// MoveThreadToSleepQueue marks a thread as blocked until simulated time
+//	reaches "wakeTime".  The caller must then put it to sleep.
+//
+//	Assumes interrupts are disabled.
+func (s *Scheduler) MoveThreadToSleepQueue(thread interfaces.IThread, wakeTime int) {
+	utils.Debug('t', "Putting thread %q to sleep until time %d.\n", thread, wakeTime)
+
+	thread.SetStatus(enums.Blocked)
+	// Keep the queue ordered by wake-up time; threads waking up at the
+	// same time do so in the order they went to sleep
+	e := s.listOfSleepingThreads.Back()
+	for e != nil && e.Value.(sleepingThread).wakeTime > wakeTime {
+		e = e.Prev()
+	}
+	if e == nil {
+		s.listOfSleepingThreads.PushFront(sleepingThread{thread, wakeTime})
+	} else {
+		s.listOfSleepingThreads.InsertAfter(sleepingThread{thread, wakeTime}, e)
+	}
+}
+
+// WakeSleepingThreads moves every sleeping thread whose time has come to
+//	the ready list.  Called from the timer interrupt handler.
+//
+//	Assumes interrupts are disabled.
+func (s *Scheduler) WakeSleepingThreads() {
+	for e := s.listOfSleepingThreads.Front(); e != nil; e = s.listOfSleepingThreads.Front() {
+		sleeper := e.Value.(sleepingThread)
+		if sleeper.wakeTime > global.Stats.TotalTicks {
+			break
+		}
+		s.listOfSleepingThreads.Remove(e)
+		utils.Debug('t', "Waking up thread %q at time %d.\n", sleeper.thread, global.Stats.TotalTicks)
+		s.MoveThreadToReadyQueue(sleeper.thread)
+	}
+}
+
+// HasSleepingThreads checks whether some thread is waiting for its wake-up
+//	time.  If so, the machine has to keep running even if nothing else is
+//	going on.
+func (s *Scheduler) HasSleepingThreads() bool {
+	return s.listOfSleepingThreads.Len() > 0
+}
+
 // Print prints the ready list
 func (s *Scheduler) Print() {
 	fmt.Println("Ready list contents")
@@ -77,15 +123,9 @@ func (s *Scheduler) ScheduleThread(nextThread interfaces.IThread) {
 
 	utils.Debug('t', "Now in thread %q\n", global.CurrentThread)
 
-	// If the old thread gave up the processor because it was finishing,
-	// we need to delete its carcass.  Note we cannot delete the thread
-	// before now (for example, in NachOSThread::FinishThread()), because up to this
-	// point, we were still running on the old thread's stack!
-	if global.ThreadToBeDestroyed != nil {
-		global.ThreadToBeDestroyed = nil
-	}
+	destroyFinishedThread()
 
-	if global.CurrentThread.Space != nil { // if there is an address space
+	if global.CurrentThread.Space() != nil { // if there is an address space
 		global.CurrentThread.RestoreUserState() // to restore, do it.
 		global.CurrentThread.Space().RestoreContextOnSwitch()
 	}
@@ -101,11 +141,27 @@ func (s *Scheduler) SelectNextReadyThread() interfaces.IThread {
 	return s.listOfReadyThreads.Remove(s.listOfReadyThreads.Front()).(interfaces.IThread)
 }
 
+// _switch passes the CPU from the goroutine running "oldThread" to the
+// one running "nextThread", and waits until "oldThread" gets it back.
+//
+// Only one goroutine runs Nachos code at a time: the one whose thread
+// holds the CPU.  The others wait in _switch (or, for threads which have
+// not started, before calling their function) for their turn.
 func _switch(oldThread, nextThread interfaces.IThread) {
-	// If I don't this, then the old thread loses context
-	// and never switches back again
-	if oldThread != nextThread {
-		global.ControlChannel[oldThread.PID()] <- 0
-		global.ControlChannel[nextThread.PID()] <- 1
+	if oldThread == nextThread {
+		return
+	}
+	nextThread.(*Thread).control <- true
+	oldThread.(*Thread).waitForTurn()
+}
+
+// destroyFinishedThread deletes the carcass of the thread which gave up
+// the CPU because it was finishing, if there is one.  Note we cannot
+// delete the thread before now (for example, in FinishThread), because up
+// to this point, we were still running on the old thread's goroutine!
+func destroyFinishedThread() {
+	if global.ThreadToBeDestroyed != nil {
+		global.ThreadToBeDestroyed.(*Thread).destroy()
+		global.ThreadToBeDestroyed = nil
 	}
 }
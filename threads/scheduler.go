@@ -13,7 +13,14 @@ import (
 // the data structures and operations needed to keep track of which
 // thread is running, and which threads are ready but not running.
 type Scheduler struct {
-	listOfReadyThreads *list.List
+	listOfReadyThreads    *list.List
+	listOfSleepingThreads *list.List // of sleepingThread, by wake-up time
+}
+
+// sleepingThread is a thread waiting for simulated time to reach "wakeTime"
+type sleepingThread struct {
+	thread   interfaces.IThread
+	wakeTime int
 }
 
 // Check if Scheduler implements IScheduler
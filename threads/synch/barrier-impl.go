@@ -0,0 +1,49 @@
+package synch
+
+import (
+	"container/list"
+
+	"github.com/yashsriv/go-nachos/enums"
+	"github.com/yashsriv/go-nachos/global"
+	"github.com/yashsriv/go-nachos/interfaces"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// Init is used to initialize a barrier for "count" threads
+func (b *Barrier) Init(debugName string, count int) {
+	utils.Assert(count > 0, "A barrier needs at least one thread")
+	b.name = debugName
+	b.count = count
+	b.arrived = 0
+	b.round = 0
+	b.queue = list.New()
+}
+
+// Wait blocks until all the threads have reached the barrier.  The last
+// one to arrive wakes up the others and starts a new round; as with
+// Semaphore.P, interrupts are disabled so that no thread can slip
+// between two rounds.
+func (b *Barrier) Wait() {
+	oldLevel := global.Interrupt.SetLevel(enums.IntOff)
+	b.arrived++
+	utils.Debug('s', "%s at barrier %s, round %d, arrived = %d of %d\n",
+		global.CurrentThread, b.name, b.round, b.arrived, b.count)
+	if b.arrived < b.count {
+		b.queue.PushBack(global.CurrentThread)
+		global.CurrentThread.PutThreadToSleep()
+	} else {
+		utils.Debug('s', "Barrier %s, round %d complete\n", b.name, b.round)
+		for b.queue.Front() != nil {
+			thread := b.queue.Remove(b.queue.Front()).(interfaces.IThread)
+			global.Scheduler.MoveThreadToReadyQueue(thread)
+		}
+		b.arrived = 0
+		b.round++
+	}
+	global.Interrupt.SetLevel(oldLevel)
+}
+
+// Name is a getter for the name field
+func (b *Barrier) Name() string {
+	return b.name
+}
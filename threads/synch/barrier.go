@@ -0,0 +1,31 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package synch
+
+import (
+	"container/list"
+
+	"github.com/yashsriv/go-nachos/interfaces"
+)
+
+// Barrier makes a group of N threads wait for each other.  It has a
+// single operation:
+//
+//	Wait() -- wait until all N threads have called Wait, then
+//	let all of them go on
+//
+// The barrier resets itself once everybody has gone through, so the same
+// group can use it over and over, for example once per round of some
+// computation.
+type Barrier struct {
+	name    string
+	count   int        // number of threads that must wait for each other
+	arrived int        // number of threads waiting in this round
+	round   int        // number of rounds completed, for debugging
+	queue   *list.List // threads waiting for the others
+}
+
+var _ interfaces.IBarrier = &Barrier{}
+
+// Implemented in threads/synch/barrier-impl.go
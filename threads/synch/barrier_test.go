@@ -0,0 +1,28 @@
+package synch
+
+import (
+	"testing"
+
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+func TestBarrierReuse(t *testing.T) {
+	for _, seed := range testSeeds {
+		startNachos(seed)
+		barrier := &Barrier{}
+		barrier.Init("rounds", 4)
+
+		const rounds = 5
+		var arrived [rounds]int
+		runThreads("worker", 4, func(i int) {
+			for round := 0; round < rounds; round++ {
+				work(1 + utils.Random()%50)
+				arrived[round]++
+				barrier.Wait()
+				if arrived[round] != 4 {
+					t.Errorf("seed %d: left round %d with %d of 4 threads there", seed, round, arrived[round])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package synch
+
+import (
+	"container/list"
+
+	"github.com/yashsriv/go-nachos/enums"
+	"github.com/yashsriv/go-nachos/global"
+	"github.com/yashsriv/go-nachos/interfaces"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// Init is used to initialize a condition variable, with nobody waiting
+func (c *Condition) Init(debugName string) {
+	c.name = debugName
+	c.lock = nil
+	c.queue = list.New()
+}
+
+// checkLock makes sure "conditionLock" is held, and is the one every
+// other thread uses with this condition variable
+func (c *Condition) checkLock(conditionLock interfaces.ILock) {
+	utils.Assert(conditionLock.IsHeldByCurrentThread(), "The condition lock must be held")
+	utils.Assert(c.lock == nil || c.lock == conditionLock, "A condition variable must always be used with the same lock")
+}
+
+// Wait releases "conditionLock", sleeps until signaled, and takes the lock
+// again before returning.  Releasing the lock and going to sleep happen
+// with interrupts disabled, so a Signal can't get in between and be
+// lost.
+func (c *Condition) Wait(conditionLock interfaces.ILock) {
+	oldLevel := global.Interrupt.SetLevel(enums.IntOff)
+	c.checkLock(conditionLock)
+	c.lock = conditionLock
+	utils.Debug('s', "%s waiting on condition %s\n", global.CurrentThread, c.name)
+	c.queue.PushBack(global.CurrentThread)
+	conditionLock.Release()
+	global.CurrentThread.PutThreadToSleep()
+	global.Interrupt.SetLevel(oldLevel)
+	conditionLock.Acquire()
+}
+
+// Signal wakes up one thread waiting on the condition, if there is any
+func (c *Condition) Signal(conditionLock interfaces.ILock) {
+	oldLevel := global.Interrupt.SetLevel(enums.IntOff)
+	c.checkLock(conditionLock)
+	if c.queue.Front() != nil {
+		thread := c.queue.Remove(c.queue.Front()).(interfaces.IThread)
+		utils.Debug('s', "%s signaling %s on condition %s\n", global.CurrentThread, thread, c.name)
+		global.Scheduler.MoveThreadToReadyQueue(thread)
+	}
+	if c.queue.Front() == nil {
+		c.lock = nil
+	}
+	global.Interrupt.SetLevel(oldLevel)
+}
+
+// Broadcast wakes up every thread waiting on the condition
+func (c *Condition) Broadcast(conditionLock interfaces.ILock) {
+	oldLevel := global.Interrupt.SetLevel(enums.IntOff)
+	c.checkLock(conditionLock)
+	utils.Debug('s', "%s broadcasting on condition %s\n", global.CurrentThread, c.name)
+	for c.queue.Front() != nil {
+		thread := c.queue.Remove(c.queue.Front()).(interfaces.IThread)
+		global.Scheduler.MoveThreadToReadyQueue(thread)
+	}
+	c.lock = nil
+	global.Interrupt.SetLevel(oldLevel)
+}
+
+// Name is a getter for the name field
+func (c *Condition) Name() string {
+	return c.name
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package synch
+
+import (
+	"container/list"
+
+	"github.com/yashsriv/go-nachos/interfaces"
+)
+
+// Condition is a condition variable.  It has no value, but threads may
+// be queued, waiting on it.  These are the three operations on a
+// condition variable:
+//
+//	Wait() -- release the lock, relinquish the CPU until signaled,
+//	then re-acquire the lock
+//
+//	Signal() -- wake up a thread, if there are any waiting on
+//	the condition
+//
+//	Broadcast() -- wake up all threads waiting on the condition
+//
+// All operations on a condition variable must be made while
+// the current thread has acquired a lock.  Indeed, all accesses
+// to a given condition variable must be protected by the same lock.
+// In other words, mutual exclusion must be enforced among threads calling
+// the condition variable operations.
+//
+// These are "Mesa-style" condition variables: when Signal or Broadcast
+// wakes up another thread, it simply puts the thread on the ready list,
+// and it is the responsibility of the woken thread to re-acquire the
+// lock, and to check again that the condition it waited for holds.
+type Condition struct {
+	name  string
+	lock  interfaces.ILock // lock the waiters are using, nil if none
+	queue *list.List       // threads waiting in Wait
+}
+
+var _ interfaces.ICondition = &Condition{}
+
+// Implemented in threads/synch/condition-impl.go
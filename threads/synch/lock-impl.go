@@ -0,0 +1,60 @@
+package synch
+
+import (
+	"container/list"
+
+	"github.com/yashsriv/go-nachos/enums"
+	"github.com/yashsriv/go-nachos/global"
+	"github.com/yashsriv/go-nachos/interfaces"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// Init is used to initialize a lock, which starts out FREE
+func (l *Lock) Init(debugName string) {
+	l.name = debugName
+	l.owner = nil
+	l.queue = list.New()
+}
+
+// Acquire waits until the lock is FREE, then takes it.  As with
+// Semaphore.P, checking and taking the lock must be atomic, so
+// interrupts are disabled meanwhile.
+//
+// A thread must not acquire a lock it already holds.
+func (l *Lock) Acquire() {
+	oldLevel := global.Interrupt.SetLevel(enums.IntOff)
+	utils.Assert(!l.IsHeldByCurrentThread(), "A thread cannot acquire a lock it already holds")
+	utils.Debug('s', "%s acquiring lock %s\n", global.CurrentThread, l.name)
+	for l.owner != nil { // lock is BUSY, go to sleep
+		l.queue.PushBack(global.CurrentThread)
+		global.CurrentThread.PutThreadToSleep()
+	}
+	l.owner = global.CurrentThread
+	utils.Debug('s', "%s acquired lock %s\n", global.CurrentThread, l.name)
+	global.Interrupt.SetLevel(oldLevel)
+}
+
+// Release frees the lock, waking up a thread waiting in Acquire if there
+// is one.  Only the thread holding the lock may release it.
+func (l *Lock) Release() {
+	oldLevel := global.Interrupt.SetLevel(enums.IntOff)
+	utils.Assert(l.IsHeldByCurrentThread(), "Only the thread holding a lock can release it")
+	utils.Debug('s', "%s releasing lock %s\n", global.CurrentThread, l.name)
+	l.owner = nil
+	if l.queue.Front() != nil {
+		thread := l.queue.Remove(l.queue.Front()).(interfaces.IThread)
+		global.Scheduler.MoveThreadToReadyQueue(thread)
+	}
+	global.Interrupt.SetLevel(oldLevel)
+}
+
+// IsHeldByCurrentThread checks whether the running thread holds the lock.
+// Useful for checks in Release, and in Condition variable operations.
+func (l *Lock) IsHeldByCurrentThread() bool {
+	return l.owner != nil && l.owner == global.CurrentThread
+}
+
+// Name is a getter for the name field
+func (l *Lock) Name() string {
+	return l.name
+}
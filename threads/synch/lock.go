@@ -0,0 +1,31 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package synch
+
+import (
+	"container/list"
+
+	"github.com/yashsriv/go-nachos/interfaces"
+)
+
+// Lock is a mutual exclusion lock.  A lock can be BUSY or FREE.
+// There are only two operations allowed on a lock:
+//
+//	Acquire -- wait until the lock is FREE, then set it to BUSY
+//
+//	Release -- set lock to be FREE, waking up a thread waiting
+//	in Acquire if necessary
+//
+// In addition, by convention, only the thread that acquired the lock
+// may release it.  As with semaphores, you can't read the lock value
+// (because the value might change immediately after you read it).
+type Lock struct {
+	name  string
+	owner interfaces.IThread // thread holding the lock, nil if FREE
+	queue *list.List         // threads waiting in Acquire
+}
+
+var _ interfaces.ILock = &Lock{}
+
+// Implemented in threads/synch/lock-impl.go
@@ -0,0 +1,115 @@
+package synch
+
+import (
+	"container/list"
+
+	"github.com/yashsriv/go-nachos/enums"
+	"github.com/yashsriv/go-nachos/global"
+	"github.com/yashsriv/go-nachos/interfaces"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// Init is used to initialize a reader-writer lock, which nobody holds at
+// first.
+//
+//	"fair" -- if true, readers waiting when a writer releases the lock
+//	get it before the next writer
+func (rw *RWLock) Init(debugName string, fair bool) {
+	rw.name = debugName
+	rw.fair = fair
+	rw.readers = 0
+	rw.writer = nil
+	rw.waitingReaders = list.New()
+	rw.waitingWriters = list.New()
+}
+
+// AcquireRead waits until the lock can be held for reading, then holds
+// it.  As with Semaphore.P, interrupts are disabled so that checking and
+// taking the lock is atomic.
+func (rw *RWLock) AcquireRead() {
+	oldLevel := global.Interrupt.SetLevel(enums.IntOff)
+	utils.Assert(rw.writer != global.CurrentThread, "A thread cannot read a lock it holds for writing")
+	utils.Debug('s', "%s acquiring lock %s for reading, readers = %d\n", global.CurrentThread, rw.name, rw.readers)
+	if rw.writer == nil && rw.waitingWriters.Front() == nil {
+		rw.readers++
+	} else {
+		// Whoever lets us in counts us as a reader
+		rw.waitingReaders.PushBack(global.CurrentThread)
+		global.CurrentThread.PutThreadToSleep()
+	}
+	utils.Debug('s', "%s holds lock %s for reading, readers = %d\n", global.CurrentThread, rw.name, rw.readers)
+	global.Interrupt.SetLevel(oldLevel)
+}
+
+// ReleaseRead gives up the lock held for reading.  The last reader out
+// lets a waiting writer in.
+func (rw *RWLock) ReleaseRead() {
+	oldLevel := global.Interrupt.SetLevel(enums.IntOff)
+	utils.Assert(rw.readers > 0, "The lock should be held for reading")
+	rw.readers--
+	utils.Debug('s', "%s released lock %s for reading, readers = %d\n", global.CurrentThread, rw.name, rw.readers)
+	if rw.readers == 0 {
+		rw.wakeWriter()
+	}
+	global.Interrupt.SetLevel(oldLevel)
+}
+
+// AcquireWrite waits until nobody holds the lock, then holds it for
+// writing
+func (rw *RWLock) AcquireWrite() {
+	oldLevel := global.Interrupt.SetLevel(enums.IntOff)
+	utils.Assert(rw.writer != global.CurrentThread, "A thread cannot acquire a lock it already holds")
+	utils.Debug('s', "%s acquiring lock %s for writing, readers = %d\n", global.CurrentThread, rw.name, rw.readers)
+	if rw.writer == nil && rw.readers == 0 {
+		rw.writer = global.CurrentThread
+	} else {
+		// Whoever lets us in makes us the writer
+		rw.waitingWriters.PushBack(global.CurrentThread)
+		global.CurrentThread.PutThreadToSleep()
+	}
+	utils.Assert(rw.writer == global.CurrentThread, "The lock should have been handed over")
+	utils.Debug('s', "%s holds lock %s for writing\n", global.CurrentThread, rw.name)
+	global.Interrupt.SetLevel(oldLevel)
+}
+
+// ReleaseWrite gives up the lock held for writing, and lets either the
+// next writer or all the waiting readers in
+func (rw *RWLock) ReleaseWrite() {
+	oldLevel := global.Interrupt.SetLevel(enums.IntOff)
+	utils.Assert(rw.writer == global.CurrentThread, "Only the thread holding a lock can release it")
+	utils.Debug('s', "%s released lock %s for writing\n", global.CurrentThread, rw.name)
+	rw.writer = nil
+	if rw.fair && rw.waitingReaders.Front() != nil {
+		rw.wakeReaders()
+	} else if !rw.wakeWriter() {
+		rw.wakeReaders()
+	}
+	global.Interrupt.SetLevel(oldLevel)
+}
+
+// wakeWriter hands the lock over to the first waiting writer.
+//
+// Returns false if no writer is waiting.
+func (rw *RWLock) wakeWriter() bool {
+	if rw.waitingWriters.Front() == nil {
+		return false
+	}
+	thread := rw.waitingWriters.Remove(rw.waitingWriters.Front()).(interfaces.IThread)
+	rw.writer = thread
+	global.Scheduler.MoveThreadToReadyQueue(thread)
+	return true
+}
+
+// wakeReaders hands the lock over to every waiting reader
+func (rw *RWLock) wakeReaders() {
+	for rw.waitingReaders.Front() != nil {
+		thread := rw.waitingReaders.Remove(rw.waitingReaders.Front()).(interfaces.IThread)
+		rw.readers++
+		global.Scheduler.MoveThreadToReadyQueue(thread)
+	}
+}
+
+// Name is a getter for the name field
+func (rw *RWLock) Name() string {
+	return rw.name
+}
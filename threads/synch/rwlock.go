@@ -0,0 +1,39 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package synch
+
+import (
+	"container/list"
+
+	"github.com/yashsriv/go-nachos/interfaces"
+)
+
+// RWLock is a reader-writer lock.  Any number of readers can hold it at
+// the same time, or else a single writer:
+//
+//	AcquireRead -- wait until no writer holds or waits for the lock,
+//	then hold it for reading
+//
+//	AcquireWrite -- wait until nobody holds the lock, then hold it
+//	for writing
+//
+// Writers are preferred: once a writer is waiting, new readers wait
+// behind it, so that a steady stream of readers can't keep it out.
+// Preferring writers can in turn starve readers; a "fair" lock lets the
+// readers waiting when a writer is done in before the next writer.
+//
+// The lock is handed over directly to the threads it wakes up, so a
+// woken thread holds the lock as soon as it runs.
+type RWLock struct {
+	name           string
+	fair           bool               // let waiting readers in after each writer
+	readers        int                // number of threads holding the lock for reading
+	writer         interfaces.IThread // thread holding the lock for writing, if any
+	waitingReaders *list.List         // threads waiting in AcquireRead
+	waitingWriters *list.List         // threads waiting in AcquireWrite
+}
+
+var _ interfaces.IRWLock = &RWLock{}
+
+// Implemented in threads/synch/rwlock-impl.go
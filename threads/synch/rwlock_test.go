@@ -0,0 +1,142 @@
+package synch
+
+import (
+	"testing"
+
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// maxTries bounds the loops which go on until some other thread gets the
+// lock, so that a starved thread fails the test instead of hanging it
+const maxTries = 1000
+
+func TestRWLockConcurrentReaders(t *testing.T) {
+	for _, seed := range testSeeds {
+		startNachos(seed)
+		lock := &RWLock{}
+		lock.Init("readers", false)
+
+		reading, mostReading := 0, 0
+		runThreads("reader", 5, func(i int) {
+			for j := 0; j < 3; j++ {
+				lock.AcquireRead()
+				reading++
+				if reading > mostReading {
+					mostReading = reading
+				}
+				work(50)
+				reading--
+				lock.ReleaseRead()
+			}
+		})
+
+		if mostReading < 2 {
+			t.Errorf("seed %d: at most %d reader held the lock at once", seed, mostReading)
+		}
+	}
+}
+
+func TestRWLockWriterExclusion(t *testing.T) {
+	for _, fair := range []bool{false, true} {
+		for _, seed := range testSeeds {
+			startNachos(seed)
+			lock := &RWLock{}
+			lock.Init("exclusion", fair)
+
+			reading, writing := 0, 0
+			runThreads("user", 6, func(i int) {
+				for j := 0; j < 10; j++ {
+					if i%2 == 0 {
+						lock.AcquireWrite()
+						writing++
+						for k := 0; k < 10; k++ {
+							if writing != 1 || reading != 0 {
+								t.Errorf("seed %d, fair %v: writer with %d writers and %d readers",
+									seed, fair, writing, reading)
+							}
+							work(1 + utils.Random()%5)
+						}
+						writing--
+						lock.ReleaseWrite()
+					} else {
+						lock.AcquireRead()
+						reading++
+						for k := 0; k < 10; k++ {
+							if writing != 0 {
+								t.Errorf("seed %d, fair %v: reader with %d writers", seed, fair, writing)
+							}
+							work(1 + utils.Random()%5)
+						}
+						reading--
+						lock.ReleaseRead()
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestRWLockFairWritersNotStarved(t *testing.T) {
+	for _, seed := range testSeeds {
+		startNachos(seed)
+		lock := &RWLock{}
+		lock.Init("writers", true)
+
+		const writers, writes = 2, 5
+		written := 0
+		runThreads("user", 6, func(i int) {
+			if i < writers {
+				for j := 0; j < writes; j++ {
+					lock.AcquireWrite()
+					written++
+					work(5)
+					lock.ReleaseWrite()
+				}
+				return
+			}
+			// Keep the lock busy with readers until the writers are done
+			tries := 0
+			for ; written < writers*writes && tries < maxTries; tries++ {
+				lock.AcquireRead()
+				work(1 + utils.Random()%20)
+				lock.ReleaseRead()
+			}
+			if tries == maxTries {
+				t.Errorf("seed %d: writers starved, %d of %d writes done", seed, written, writers*writes)
+			}
+		})
+	}
+}
+
+func TestRWLockFairReadersGoNext(t *testing.T) {
+	for _, seed := range testSeeds {
+		startNachos(seed)
+		lock := &RWLock{}
+		lock.Init("readers", true)
+
+		// A reader waiting for a fair lock gets it as soon as the writer
+		// holding it is done, before any other writer
+		const writers = 3
+		written := 0
+		runThreads("user", writers+3, func(i int) {
+			if i < writers {
+				for j := 0; j < 10; j++ {
+					lock.AcquireWrite()
+					work(1 + utils.Random()%20)
+					written++
+					lock.ReleaseWrite()
+				}
+				return
+			}
+			for j := 0; j < 3; j++ {
+				work(1 + utils.Random()%50)
+				before := written
+				lock.AcquireRead()
+				if written > before+1 {
+					t.Errorf("seed %d: reader waited for %d writers", seed, written-before)
+				}
+				lock.ReleaseRead()
+			}
+		})
+	}
+}
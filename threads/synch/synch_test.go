@@ -0,0 +1,71 @@
+package synch
+
+import (
+	"fmt"
+
+	"github.com/yashsriv/go-nachos/enums"
+	"github.com/yashsriv/go-nachos/global"
+	"github.com/yashsriv/go-nachos/machine"
+	"github.com/yashsriv/go-nachos/threads"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// Seeds the tests are run with.  Every seed gives another interleaving
+// of the threads, as with -rs.
+var testSeeds = []int64{1, 7, 42, 1234, 98765}
+
+// startNachos sets up the machine as nachos does with "-rs seed": the
+// timer goes off at random times and makes the running thread yield.
+// The caller becomes the main thread.
+func startNachos(seed int64) {
+	utils.RandomInit(seed)
+
+	global.Stats = utils.Statistics{}
+
+	global.Interrupt = &machine.Interrupt{}
+	global.Interrupt.Init()
+
+	global.Scheduler = &threads.Scheduler{}
+	global.Scheduler.Init()
+
+	global.Timer = &machine.Timer{}
+	global.Timer.Init(func(arg interface{}) {
+		global.Scheduler.WakeSleepingThreads()
+		if global.Interrupt.GetStatus() != enums.IdleMode {
+			global.Interrupt.YieldOnReturn()
+		}
+	}, nil, true)
+
+	global.ThreadToBeDestroyed = nil
+	global.CurrentThread = &threads.Thread{}
+	global.CurrentThread.Init("main")
+	global.CurrentThread.SetStatus(enums.Running)
+	global.Interrupt.Enable()
+}
+
+// runThreads forks "n" threads running "function" with their index, and
+// waits for all of them to be done
+func runThreads(name string, n int, function func(i int)) {
+	done := &Semaphore{}
+	done.Init(name+" done", 0)
+	for i := 0; i < n; i++ {
+		t := &threads.Thread{}
+		t.Init(fmt.Sprintf("%s %d", name, i))
+		t.ThreadFork(func(arg interface{}) {
+			function(arg.(int))
+			done.V()
+		}, i)
+	}
+	for i := 0; i < n; i++ {
+		done.P()
+	}
+}
+
+// work lets simulated time pass, "ticks" times, giving the timer a chance
+// to switch to another thread
+func work(ticks int) {
+	for i := 0; i < ticks; i++ {
+		global.Interrupt.SetLevel(enums.IntOff)
+		global.Interrupt.SetLevel(enums.IntOn)
+	}
+}
@@ -4,6 +4,8 @@
 package threads
 
 import (
+	"runtime"
+
 	"github.com/yashsriv/go-nachos/enums"
 	"github.com/yashsriv/go-nachos/global"
 	"github.com/yashsriv/go-nachos/interfaces"
@@ -77,6 +79,25 @@ func (t *Thread) PutThreadToSleep() {
 
 }
 
+// Sleep relinquishes the CPU for "ticks" of simulated time.  The thread
+//	is woken up by the timer interrupt handler once the time has passed,
+//	so it may sleep a little longer, until the next timer interrupt.
+//
+//	A sleep of no time at all is the same as YieldCPU.
+func (t *Thread) Sleep(ticks int) {
+	if ticks <= 0 {
+		t.YieldCPU()
+		return
+	}
+	oldLevel := global.Interrupt.SetLevel(enums.IntOff)
+
+	utils.Assert(t == global.CurrentThread.(*Thread), "Only current thread can sleep")
+
+	global.Scheduler.MoveThreadToSleepQueue(t, global.Stats.TotalTicks+ticks)
+	t.PutThreadToSleep()
+	global.Interrupt.SetLevel(oldLevel)
+}
+
 // SetStatus sets the thread's status
 func (t *Thread) SetStatus(st enums.ThreadStatus) {
 	t.status = st
@@ -108,6 +129,13 @@ func (t *Thread) SaveUserState() {
 	}
 }
 
+// ResetReturnValue sets the saved return value register of a user thread
+// to 0.  Used on fork, so that the child sees 0 as the return value while
+// the parent sees the PID of the child.
+func (t *Thread) ResetReturnValue() {
+	t.userRegisters[2] = 0
+}
+
 // ThreadFork invokes function, allowing caller and callee to execute
 //	concurrently.
 //
@@ -167,12 +195,20 @@ func (t *Thread) YieldCPU() {
 	global.Interrupt.SetLevel(oldLevel)
 }
 
-// Init initializes our thread
+// Init initializes our thread.  Every thread gets a PID no other live
+// thread has, and the thread that created it becomes its parent.
 func (t *Thread) Init(name string) {
+	var ok bool
 	t.name = name
 	t.stateRestored = true
-	t.pid = 0
-	t.ppid = NO_PARENT
+	t.pid, ok = threadRegistry.add(t)
+	utils.Assert(ok, "Too many threads are alive")
+	t.control = make(chan bool, 1)
+	if global.CurrentThread != nil {
+		t.ppid = global.CurrentThread.PID()
+	} else {
+		t.ppid = NO_PARENT
+	}
 }
 
 // CreateThreadStack allocates and initializes an execution stack.  The stack is
@@ -185,12 +221,31 @@ func (t *Thread) Init(name string) {
 //	"arg" is the parameter to be passed to the procedure
 func (t *Thread) createThreadStack(function utils.VoidFunction, arg interface{}) {
 	go func() {
+		t.waitForTurn() // the thread only starts once it is scheduled
+		destroyFinishedThread()
 		global.Interrupt.Enable()
 		function(arg)
 		global.CurrentThread.FinishThread()
 	}()
 }
 
+// waitForTurn blocks the goroutine running the thread until the thread
+// is switched to.  If the thread is destroyed instead, the goroutine
+// exits.
+func (t *Thread) waitForTurn() {
+	if _, ok := <-t.control; !ok {
+		runtime.Goexit()
+	}
+}
+
+// destroy releases what the thread holds, once it has finished and some
+// other thread is running.  The goroutine which ran it exits.
+func (t *Thread) destroy() {
+	utils.Debug('t', "Destroying thread %q\n", t)
+	threadRegistry.remove(t)
+	close(t.control)
+}
+
 // PID getter
 func (t *Thread) PID() int {
 	return t.pid
@@ -201,6 +256,16 @@ func (t *Thread) PPID() int {
 	return t.ppid
 }
 
+// NumInstr returns the number of user instructions the thread has executed
+func (t *Thread) NumInstr() int {
+	return t.numInstr
+}
+
+// IncrementNumInstr counts one more user instruction executed by the thread
+func (t *Thread) IncrementNumInstr() {
+	t.numInstr++
+}
+
 // Space getter
 func (t *Thread) Space() interfaces.IProcessAddressSpace {
 	return t.space
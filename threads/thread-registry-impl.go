@@ -0,0 +1,82 @@
+package threads
+
+import (
+	"github.com/yashsriv/go-nachos/global"
+	"github.com/yashsriv/go-nachos/interfaces"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// Init initializes an empty registry, with every PID free
+func (r *ThreadRegistry) Init() {
+	r.pids = &utils.BitMap{}
+	r.pids.Init(global.MaxProcesses)
+	r.holds = [global.MaxProcesses]int{}
+	r.threads = make(map[int]*Thread)
+}
+
+// add gives "t" a free PID, held by the thread, and records it
+//
+// Returns false if every PID is taken.
+func (r *ThreadRegistry) add(t *Thread) (int, bool) {
+	pid := r.pids.Find()
+	if pid == -1 {
+		return 0, false
+	}
+	r.holds[pid] = 1
+	r.threads[pid] = t
+	utils.Debug('t', "Thread %q gets PID %d\n", t, pid)
+	return pid, true
+}
+
+// remove forgets about "t", which is being destroyed, and gives up its
+// hold on its PID
+func (r *ThreadRegistry) remove(t *Thread) {
+	utils.Assert(r.threads[t.pid] == t, "Only a registered thread can be removed")
+	delete(r.threads, t.pid)
+	r.release(t.pid)
+}
+
+// hold keeps "pid", which must be taken, from being reused until it is
+// released
+func (r *ThreadRegistry) hold(pid int) {
+	utils.Assert(r.holds[pid] > 0, "Only a PID in use can be held")
+	r.holds[pid]++
+}
+
+// release gives up a hold on "pid".  Once nobody holds it, it is free to
+// be given to a new thread.
+func (r *ThreadRegistry) release(pid int) {
+	utils.Assert(r.holds[pid] > 0, "Only a PID in use can be released")
+	r.holds[pid]--
+	if r.holds[pid] == 0 {
+		utils.Debug('t', "PID %d is free\n", pid)
+		r.pids.Clear(pid)
+	}
+}
+
+// lookup returns the live thread whose PID is "pid"
+func (r *ThreadRegistry) lookup(pid int) (interfaces.IThread, bool) {
+	t, ok := r.threads[pid]
+	if !ok {
+		return nil, false
+	}
+	return t, true
+}
+
+// LookupThread returns the thread whose PID is "pid", if it is still
+// around
+func LookupThread(pid int) (interfaces.IThread, bool) {
+	return threadRegistry.lookup(pid)
+}
+
+// HoldPID keeps "pid", the PID of a live thread, from being given to
+// another thread until ReleasePID is called, even if the thread is
+// destroyed in the meantime.
+func HoldPID(pid int) {
+	threadRegistry.hold(pid)
+}
+
+// ReleasePID undoes HoldPID
+func ReleasePID(pid int) {
+	threadRegistry.release(pid)
+}
@@ -0,0 +1,27 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package threads
+
+import (
+	"github.com/yashsriv/go-nachos/global"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// ThreadRegistry hands out PIDs and keeps track of which thread has which.
+//
+// A PID is taken for as long as anybody holds it.  The thread itself
+// holds its PID until it is destroyed; others (such as the process table
+// of the kernel, for a process whose exit status has not been collected)
+// may hold it longer, so that it is not given to another thread while
+// they still refer to it.  PIDs which nobody holds are reused.
+type ThreadRegistry struct {
+	pids    *utils.BitMap // a set bit means the PID is taken
+	holds   [global.MaxProcesses]int
+	threads map[int]*Thread // the live threads, by PID
+}
+
+// threadRegistry is the registry of all threads
+var threadRegistry = &ThreadRegistry{}
+
+// Implemented in thread-registry-impl.go
@@ -32,6 +32,12 @@ type Thread struct {
 	pid    int
 	ppid   int
 
+	numInstr int // number of user instructions executed
+
+	// Gets a value when it is this thread's turn to run on the CPU, and
+	// is closed when the thread is destroyed
+	control chan bool
+
 	userRegisters [machine.NumTotalRegs]uint32
 	stateRestored bool
 	space         interfaces.IProcessAddressSpace
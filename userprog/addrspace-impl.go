@@ -2,78 +2,416 @@ package userprog
 
 import (
 	"encoding/binary"
+	"errors"
 	"math"
 	"os"
 
 	"github.com/yashsriv/go-nachos/global"
+	"github.com/yashsriv/go-nachos/interfaces"
 	"github.com/yashsriv/go-nachos/machine"
 	"github.com/yashsriv/go-nachos/utils"
 )
 
-var mainMemoryOffset uint32
-
 func bzero(memory []byte) {
 	for i := 0; i < len(memory); i++ {
 		memory[i] = 0
 	}
 }
 
+// EnableDemandPaging makes address spaces start out with no pages in
+// memory.  Pages are loaded one at a time, on the first page fault
+// which touches them.  By default every page is loaded up front.
+//
+// Pages evicted when memory runs out are kept in a swap area on the disk
+// simulated by the UNIX file "swapFile".
+func EnableDemandPaging(swapFile string) {
+	demandPaging = true
+	backingStore.Init(swapFile)
+}
+
 // Init should be called on a process address space before anything else
-// acts as a constructor
-func (addrspace *ProcessAddressSpace) Init(filename string) {
+// acts as a constructor.  Loads the NOFF executable "filename" into memory,
+// or with demand paging, only prepares to do so.
+//
+// Returns an error, leaving the address space unusable, if the executable
+// cannot be read, is not a NOFF file or does not fit in physical memory,
+// or with demand paging, in the swap area.
+func (addrspace *ProcessAddressSpace) Init(filename string) error {
 	var executable *os.File
 
 	// Open the File
 	executable, err := os.Open(filename)
 	if err != nil {
-		utils.Panic(err)
+		return err
 	}
-	defer executable.Close()
 
 	var noffH = NoffHeader{}
 
-	binary.Read(executable, binary.LittleEndian, &noffH)
+	if err = binary.Read(executable, binary.LittleEndian, &noffH); err != nil {
+		executable.Close()
+		return err
+	}
 
-	utils.Assert(noffH.NoffMagic == NOFFMAGIC, "Executable magic value to confirm if its the right format")
+	if noffH.NoffMagic != NOFFMAGIC {
+		executable.Close()
+		return errors.New("Executable is not in NOFF format")
+	}
 
-	var offset = mainMemoryOffset
 	var size = noffH.Code.Size + noffH.InitData.Size + noffH.UninitData.Size + UserStackSize
 	var numVirtualPages = uint32(math.Ceil(float64(size) / float64(machine.PageSize)))
 	size = numVirtualPages * machine.PageSize
-	utils.Assert(numVirtualPages <= (machine.NumPhysPages-(offset/machine.PageSize)), "There should be enough number of free physical pages")
+	if !demandPaging && numVirtualPages > frameAllocator.NumFree() {
+		executable.Close()
+		return errors.New("Not enough free physical pages to load executable")
+	}
+	if !noffH.Code.fitsIn(size) || !noffH.InitData.fitsIn(size) {
+		executable.Close()
+		return errors.New("Executable segment lies outside the address space")
+	}
+	// Any page may have to be swapped out
+	if demandPaging && !backingStore.Reserve(int(numVirtualPages)) {
+		executable.Close()
+		return errors.New("Not enough swap space for the address space")
+	}
 	utils.Debug('a', "Initializing address space, num pages %d, size %d\n",
 		numVirtualPages, size)
+	utils.Debug('a', "Code segment at 0x%x, size %d; data segment at 0x%x, size %d\n",
+		noffH.Code.VirtualAddr, noffH.Code.Size, noffH.InitData.VirtualAddr, noffH.InitData.Size)
 	addrspace.kernelPageTable = make([]utils.TranslationEntry, numVirtualPages)
 	addrspace.numVirtualPages = numVirtualPages
+	addrspace.executable = executable
+	addrspace.noffH = noffH
+	addrspace.swapSlots = make(map[uint32]int)
+	addrspace.copyOnWrite = make([]bool, numVirtualPages)
 	for i := uint32(0); i < numVirtualPages; i++ {
 		addrspace.kernelPageTable[i] = utils.TranslationEntry{
 			VirtualPage:  i,
-			PhysicalPage: (offset / machine.PageSize) + i,
-			Valid:        true,
-			ReadOnly:     false,
+			PhysicalPage: 0,
+			Valid:        false,
+			ReadOnly:     noffH.Code.covers(i*machine.PageSize, (i+1)*machine.PageSize), // nothing but code
 			Use:          false,
 			Dirty:        false,
 		}
 	}
 
+	if demandPaging {
+		// Keep the executable open, to load pages from it later
+		return nil
+	}
+
+	for i := uint32(0); i < numVirtualPages; i++ {
+		frame, _ := frameAllocator.Allocate(addrspace, i)
+		if err = addrspace.loadPage(i, frame); err != nil {
+			frameAllocator.Free(frame, addrspace, i)
+			addrspace.Release()
+			return err
+		}
+	}
+	addrspace.executable.Close()
+	addrspace.executable = nil
+
+	return nil
+}
+
+// loadPage fills "frame" with the contents of virtual page "vpn", and maps
+// the page to it.
+//
+// A page which was saved to the swap area when it was evicted is read
+// back from there.  Otherwise, whatever part of the page overlaps the code
+// or initialized data segment is read from the executable; the rest of it
+// is zeroed.
+func (addrspace *ProcessAddressSpace) loadPage(vpn uint32, frame uint32) error {
 	mainMemory := global.Machine.GetMainMemory()
-	// Zero out memory
-	bzero(mainMemory[offset : offset+size])
+	page := mainMemory[frame*machine.PageSize : (frame+1)*machine.PageSize]
+	if slot, ok := addrspace.swapSlots[vpn]; ok {
+		backingStore.ReadPage(slot, page)
+		addrspace.mapPage(vpn, frame)
+		return nil
+	}
+	bzero(page)
+
+	pageStart := vpn * machine.PageSize
+	pageEnd := pageStart + machine.PageSize
+	for _, segment := range []Segment{addrspace.noffH.Code, addrspace.noffH.InitData} {
+		start, end := segment.VirtualAddr, segment.VirtualAddr+segment.Size
+		if start < pageStart {
+			start = pageStart
+		}
+		if end > pageEnd {
+			end = pageEnd
+		}
+		if start >= end {
+			continue // segment does not overlap this page
+		}
+		inFileAddr := int64(segment.InFileAddr + (start - segment.VirtualAddr))
+		if _, err := addrspace.executable.ReadAt(page[start-pageStart:end-pageStart], inFileAddr); err != nil {
+			return err
+		}
+	}
+
+	addrspace.mapPage(vpn, frame)
+	return nil
+}
+
+// mapPage marks virtual page "vpn" as present in "frame"
+func (addrspace *ProcessAddressSpace) mapPage(vpn uint32, frame uint32) {
+	entry := &addrspace.kernelPageTable[vpn]
+	entry.PhysicalPage = frame
+	entry.Valid = true
+	entry.Use = false
+	entry.Dirty = false
+}
+
+// pageOut evicts virtual page "vpn" from memory and frees its frame.
+//
+// A page which has been modified is written to the swap area, to be read
+// back when it is next faulted in.  A clean page can always be loaded
+// again from wherever it came from.
+//
+// Must be called with pagingLock held.
+func (addrspace *ProcessAddressSpace) pageOut(vpn uint32) {
+	entry := &addrspace.kernelPageTable[vpn]
+	utils.Assert(entry.Valid, "Only a page in memory can be paged out")
+	invalidateTLBEntry(addrspace, vpn)
+	frame, dirty := entry.PhysicalPage, entry.Dirty
+
+	// Writing the page out can block.  Invalidate it first, so that if the
+	// owner runs in the meantime it faults (and waits for us) instead of
+	// modifying the page under our feet.
+	entry.Valid = false
+	entry.Dirty = false
+	if addrspace.copyOnWrite[vpn] {
+		// Once back in memory, the page is ours alone
+		addrspace.copyOnWrite[vpn] = false
+		entry.ReadOnly = false
+	}
+	if dirty {
+		slot, ok := addrspace.swapSlots[vpn]
+		if !ok {
+			slot = backingStore.AllocateSlot()
+			addrspace.swapSlots[vpn] = slot
+		}
+		utils.Debug('a', "Writing dirty virtual page %d to swap slot %d\n", vpn, slot)
+		mainMemory := global.Machine.GetMainMemory()
+		backingStore.WritePage(slot, mainMemory[frame*machine.PageSize:(frame+1)*machine.PageSize])
+	}
+	frameAllocator.Free(frame, addrspace, vpn)
+}
+
+// HandlePageFault brings the page containing "virtAddr" into memory, so
+// that the faulting instruction can be restarted.  With a TLB, the fault
+// may only be a TLB miss; the translation is loaded into the TLB, after
+// bringing the page in if it isn't in memory either.
+//
+// Returns false if "virtAddr" is outside the address space or the page
+// could not be brought in.
+func (addrspace *ProcessAddressSpace) HandlePageFault(virtAddr uint32) bool {
+	vpn := virtAddr / machine.PageSize
+	if vpn >= addrspace.numVirtualPages {
+		utils.Debug('a', "Page fault at 0x%x is outside the address space\n", virtAddr)
+		return false
+	}
+
+	// Paging in and out can block on the disk; only one thread at a time
+	// gets to move pages around.
+	pagingLock.Acquire()
+	defer pagingLock.Release()
+
+	if !addrspace.kernelPageTable[vpn].Valid {
+		utils.Debug('a', "Page fault at 0x%x, loading virtual page %d\n", virtAddr, vpn)
+		global.Stats.CountPageFault()
+
+		frame, ok := frameAllocator.Allocate(addrspace, vpn)
+		if !ok && demandPaging {
+			// Memory is full, make room by evicting some page
+			frameAllocator.Evict()
+			frame, ok = frameAllocator.Allocate(addrspace, vpn)
+		}
+		if !ok {
+			return false
+		}
+		if err := addrspace.loadPage(vpn, frame); err != nil {
+			utils.Debug('a', "Could not load virtual page %d: %v\n", vpn, err)
+			frameAllocator.Free(frame, addrspace, vpn)
+			return false
+		}
+	}
+	if tlbEnabled() {
+		loadTLBEntry(addrspace, vpn)
+	}
+	return true
+}
 
-	if noffH.Code.Size > 0 {
-		utils.Debug('a', "Initializing code segment, at 0x%x, size %d\n", noffH.Code.VirtualAddr, noffH.Code.Size)
-		start := noffH.Code.VirtualAddr + offset
-		executable.ReadAt(mainMemory[start:start+noffH.Code.Size], int64(noffH.Code.InFileAddr))
+// InitFrom initializes this address space as a copy of "parent".  Used by
+// fork -- the two processes must not see each other's writes.
+//
+// Nothing is copied up front.  Every page the parent has in memory is
+// shared with the child, copy-on-write: the frame is mapped read-only in
+// both address spaces, and whichever process first writes to the page gets
+// a copy of its own (see HandleCopyOnWrite).  With demand paging, pages of
+// the parent which are only in the swap area are copied to swap slots of
+// the child.
+//
+// Returns an error if there isn't enough swap space for the child.
+func (addrspace *ProcessAddressSpace) InitFrom(iparent interfaces.IProcessAddressSpace) error {
+	parent := iparent.(*ProcessAddressSpace)
+
+	pagingLock.Acquire()
+	defer pagingLock.Release()
+
+	syncTLB() // which pages the parent has modified may only be known to the TLB
+
+	// Any page may have to be swapped out
+	if demandPaging && !backingStore.Reserve(int(parent.numVirtualPages)) {
+		return errors.New("Not enough swap space to copy address space")
+	}
+	if parent.executable != nil {
+		executable, err := os.Open(parent.executable.Name())
+		if err != nil {
+			if demandPaging {
+				backingStore.Unreserve(int(parent.numVirtualPages))
+			}
+			return err
+		}
+		addrspace.executable = executable
+	}
+	var numVirtualPages = parent.numVirtualPages
+	utils.Debug('a', "Copying address space copy-on-write, num pages %d\n", numVirtualPages)
+	addrspace.kernelPageTable = make([]utils.TranslationEntry, numVirtualPages)
+	addrspace.numVirtualPages = numVirtualPages
+	addrspace.noffH = parent.noffH
+	addrspace.swapSlots = make(map[uint32]int)
+	addrspace.copyOnWrite = make([]bool, numVirtualPages)
+
+	for i := uint32(0); i < numVirtualPages; i++ {
+		parentEntry := &parent.kernelPageTable[i]
+		addrspace.kernelPageTable[i] = utils.TranslationEntry{
+			VirtualPage:  i,
+			PhysicalPage: 0,
+			Valid:        false,
+			ReadOnly:     parentEntry.ReadOnly,
+			Use:          false,
+			Dirty:        false,
+		}
+		parentSlot, inSwap := parent.swapSlots[i]
+
+		if parentEntry.Valid {
+			if !parentEntry.ReadOnly || parent.copyOnWrite[i] {
+				invalidateTLBEntry(parent, i) // it was writable there
+				parentEntry.ReadOnly = true
+				parent.copyOnWrite[i] = true
+				addrspace.kernelPageTable[i].ReadOnly = true
+				addrspace.copyOnWrite[i] = true
+			}
+			// The child has to save the page if it gets evicted, unless it
+			// can be loaded again from the executable
+			addrspace.kernelPageTable[i].Dirty = parentEntry.Dirty || inSwap
+			addrspace.kernelPageTable[i].PhysicalPage = parentEntry.PhysicalPage
+			addrspace.kernelPageTable[i].Valid = true
+			frameAllocator.Share(parentEntry.PhysicalPage, addrspace, i)
+			continue
+		}
+
+		if inSwap {
+			contents := make([]byte, machine.PageSize)
+			backingStore.ReadPage(parentSlot, contents)
+			slot := backingStore.AllocateSlot()
+			backingStore.WritePage(slot, contents)
+			addrspace.swapSlots[i] = slot
+		}
+	}
+
+	return nil
+}
+
+// HandleCopyOnWrite gives this address space a copy of its own of the
+// copy-on-write page containing "virtAddr", which was written to, so that
+// the faulting instruction can be restarted.  If no other address space
+// shares the page any more, it is simply made writable.
+//
+// Returns false if the page is not copy-on-write -- the program really
+// tried to write to a read-only page -- or there is no memory for the copy.
+func (addrspace *ProcessAddressSpace) HandleCopyOnWrite(virtAddr uint32) bool {
+	vpn := virtAddr / machine.PageSize
+	if vpn >= addrspace.numVirtualPages {
+		return false
 	}
 
-	if noffH.InitData.Size > 0 {
-		utils.Debug('a', "Initializing data segment, at 0x%x, size %d\n", noffH.InitData.VirtualAddr, noffH.InitData.Size)
-		start := noffH.InitData.VirtualAddr + offset
-		executable.ReadAt(mainMemory[start:start+noffH.InitData.Size], int64(noffH.InitData.InFileAddr))
+	pagingLock.Acquire()
+	defer pagingLock.Release()
+
+	entry := &addrspace.kernelPageTable[vpn]
+	if !addrspace.copyOnWrite[vpn] {
+		// The page may have been paged out while we waited, which makes it
+		// private; otherwise it is read-only for good
+		return !entry.ReadOnly
 	}
+	utils.Debug('a', "Copy-on-write fault at 0x%x, virtual page %d\n", virtAddr, vpn)
+	global.Stats.NumCOWFaults++
+	invalidateTLBEntry(addrspace, vpn)
 
-	mainMemoryOffset += size
+	frame := entry.PhysicalPage
+	if frameAllocator.RefCount(frame) > 1 {
+		newFrame, ok := frameAllocator.Allocate(addrspace, vpn)
+		if !ok && demandPaging {
+			frameAllocator.Evict()
+			if !entry.Valid {
+				// Our own page was evicted, and comes back as a private
+				// copy on the next fault
+				return true
+			}
+			newFrame, ok = frameAllocator.Allocate(addrspace, vpn)
+		}
+		if !ok {
+			return false
+		}
+		mainMemory := global.Machine.GetMainMemory()
+		copy(mainMemory[newFrame*machine.PageSize:(newFrame+1)*machine.PageSize],
+			mainMemory[frame*machine.PageSize:(frame+1)*machine.PageSize])
+		frameAllocator.Free(frame, addrspace, vpn)
+		global.Stats.NumCOWFramesSaved--
+		entry.PhysicalPage = newFrame
+	}
+	entry.ReadOnly = false
+	addrspace.copyOnWrite[vpn] = false
+	if tlbEnabled() {
+		loadTLBEntry(addrspace, vpn)
+	}
+	return true
+}
 
+// translate finds the physical address of "virtAddr", for the kernel to
+// read or write on behalf of the user program.  The page is brought into
+// memory if needed, and if it is to be written, copied if it is shared
+// copy-on-write.  The use and dirty bits are set as the hardware would.
+//
+// Returns false if the user program could not have made the access
+// itself.
+func (addrspace *ProcessAddressSpace) translate(virtAddr uint32, writing bool) (uint32, bool) {
+	vpn, offset := virtAddr/machine.PageSize, virtAddr%machine.PageSize
+	if vpn >= addrspace.numVirtualPages {
+		return 0, false
+	}
+	for {
+		entry := &addrspace.kernelPageTable[vpn]
+		if !entry.Valid {
+			if !addrspace.HandlePageFault(virtAddr) {
+				return 0, false
+			}
+			continue
+		}
+		if writing && entry.ReadOnly {
+			if !addrspace.HandleCopyOnWrite(virtAddr) {
+				return 0, false
+			}
+			continue
+		}
+		entry.Use = true
+		if writing {
+			entry.Dirty = true
+		}
+		return entry.PhysicalPage*machine.PageSize + offset, true
+	}
 }
 
 // InitUserModeCPURegisters initializes registers
@@ -96,14 +434,59 @@ func (addrspace *ProcessAddressSpace) InitUserModeCPURegisters() {
 
 }
 
+// Release gives up the memory held by this address space, once the
+// process is done with it (on exec or exit).  Every frame is returned
+// to the frame allocator, and every swap slot to the swap area.
+func (addrspace *ProcessAddressSpace) Release() {
+	pagingLock.Acquire()
+	addrspace.release()
+	pagingLock.Release()
+}
+
+// release does the work of Release, with pagingLock already held
+func (addrspace *ProcessAddressSpace) release() {
+	utils.Debug('a', "Releasing address space, num pages %d\n", addrspace.numVirtualPages)
+	if addrspace == tlbSpace {
+		flushTLB()
+	}
+	for i := range addrspace.kernelPageTable {
+		if addrspace.kernelPageTable[i].Valid {
+			frameAllocator.Free(addrspace.kernelPageTable[i].PhysicalPage, addrspace, uint32(i))
+		}
+	}
+	for _, slot := range addrspace.swapSlots {
+		backingStore.FreeSlot(slot)
+	}
+	if demandPaging {
+		// Slots reserved for pages which were never swapped out
+		backingStore.Unreserve(int(addrspace.numVirtualPages) - len(addrspace.swapSlots))
+	}
+	addrspace.kernelPageTable = nil
+	addrspace.numVirtualPages = 0
+	addrspace.swapSlots = nil
+	addrspace.copyOnWrite = nil
+	if addrspace.executable != nil {
+		addrspace.executable.Close()
+		addrspace.executable = nil
+	}
+}
+
 // SaveContextOnSwitch saves machine space specific to this addrspace that needs saving
+//
+//	The TLB is flushed, saving its use and dirty bits in the page table.
 func (addrspace *ProcessAddressSpace) SaveContextOnSwitch() {
+	flushTLB()
 }
 
 // RestoreContextOnSwitch restores the machine state so that
 //	this address space can run.
 //
-//      For now, tell the machine where to find the page table.
+//      Tell the machine where to find the page table, and start out
+//      with an empty TLB.
 func (addrspace *ProcessAddressSpace) RestoreContextOnSwitch() {
+	if tlbSpace != addrspace {
+		flushTLB()
+	}
+	tlbSpace = addrspace
 	global.Machine.SetPageTable(addrspace.kernelPageTable)
 }
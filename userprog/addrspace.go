@@ -4,6 +4,8 @@
 package userprog
 
 import (
+	"os"
+
 	"github.com/yashsriv/go-nachos/interfaces"
 	"github.com/yashsriv/go-nachos/utils"
 )
@@ -11,10 +13,23 @@ import (
 // UserStackSize is the size of the user stack
 const UserStackSize = 1024
 
+// demandPaging is true if pages are to be loaded only when first accessed
+var demandPaging = false
+
+// pagingLock allows only one thread at a time to move pages in and out of
+// memory, since doing so can block on the disk
+var pagingLock interfaces.ILock
+
 // ProcessAddressSpace is a data structure to keep track of existing user programs
 type ProcessAddressSpace struct {
 	kernelPageTable []utils.TranslationEntry
 	numVirtualPages uint32
+
+	executable *os.File       // Executable to load pages from, nil once everything is loaded
+	noffH      NoffHeader     // Layout of the executable
+	swapSlots  map[uint32]int // Swap slots holding pages which were evicted
+
+	copyOnWrite []bool // Pages shared with another process until either writes to them
 }
 
 // Check if ProcessAddressSpace implements IProcessAddressSpace
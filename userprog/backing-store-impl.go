@@ -0,0 +1,68 @@
+package userprog
+
+import (
+	"github.com/yashsriv/go-nachos/disk"
+	"github.com/yashsriv/go-nachos/global"
+	"github.com/yashsriv/go-nachos/machine"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// Init sets up the swap area on the disk simulated by the UNIX file "name"
+func (bs *BackingStore) Init(name string) {
+	utils.Assert(int(machine.PageSize) == disk.SectorSize, "A page should fit exactly in a disk sector")
+
+	bs.slots = &utils.BitMap{}
+	bs.slots.Init(disk.NumSectors)
+	bs.reserved = 0
+
+	bs.disk = &disk.SynchDisk{}
+	bs.disk.Init(name)
+}
+
+// Reserve sets aside "n" free slots, to be allocated later.
+//
+// Returns false if there are not that many free slots left.
+func (bs *BackingStore) Reserve(n int) bool {
+	if bs.slots.NumClear()-bs.reserved < n {
+		utils.Debug('a', "Cannot reserve %d swap slots\n", n)
+		return false
+	}
+	bs.reserved += n
+	return true
+}
+
+// Unreserve gives back "n" reserved slots which were never allocated
+func (bs *BackingStore) Unreserve(n int) {
+	utils.Assert(n <= bs.reserved, "Only reserved swap slots can be given back")
+	bs.reserved -= n
+}
+
+// AllocateSlot finds a free slot in the swap area to hold a page, out of
+// those reserved by the caller.
+func (bs *BackingStore) AllocateSlot() int {
+	utils.Assert(bs.reserved > 0, "A swap slot should have been reserved")
+	slot := bs.slots.Find()
+	utils.Assert(slot != -1, "A reserved swap slot should be free")
+	bs.reserved--
+	return slot
+}
+
+// FreeSlot gives back a slot which no longer holds a page
+func (bs *BackingStore) FreeSlot(slot int) {
+	bs.slots.Clear(slot)
+}
+
+// ReadPage reads the page in "slot" into "page", waiting until the disk
+// is done.
+func (bs *BackingStore) ReadPage(slot int, page []byte) {
+	utils.Debug('a', "Reading page from swap slot %d\n", slot)
+	bs.disk.ReadSector(slot, page)
+	global.Stats.NumPagingReads++
+}
+
+// WritePage writes "page" to "slot", waiting until the disk is done.
+func (bs *BackingStore) WritePage(slot int, page []byte) {
+	utils.Debug('a', "Writing page to swap slot %d\n", slot)
+	bs.disk.WriteSector(slot, page)
+	global.Stats.NumPagingWrites++
+}
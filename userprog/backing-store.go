@@ -0,0 +1,28 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package userprog
+
+import (
+	"github.com/yashsriv/go-nachos/interfaces"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// BackingStore is the swap area where pages evicted from main memory are
+// kept until they are faulted in again.
+//
+// It lives on a simulated disk of its own, one page per sector, so that
+// paging pays the same latency as any other disk request.
+//
+// Every address space reserves a slot for each of its pages up front, so
+// that evicting a page never finds the swap area full.
+type BackingStore struct {
+	disk     interfaces.ISynchDisk
+	slots    *utils.BitMap // a set bit means the sector holds some page
+	reserved int           // free slots promised to address spaces
+}
+
+// backingStore is the swap area used with demand paging
+var backingStore = &BackingStore{}
+
+// Implemented in backing-store-impl.go
@@ -3,12 +3,9 @@
 
 package userprog
 
-// #include "syscall.h"
-import "C"
 import (
 	"fmt"
 
-	"github.com/yashsriv/go-nachos/console"
 	"github.com/yashsriv/go-nachos/enums"
 	"github.com/yashsriv/go-nachos/global"
 	"github.com/yashsriv/go-nachos/interfaces"
@@ -17,10 +14,9 @@ import (
 	"github.com/yashsriv/go-nachos/utils"
 )
 
-var readAvail interfaces.ISemaphore
-var writeDone interfaces.ISemaphore
-
-var initializedConsoleSemaphores = false
+// maxStringLength is the longest string a system call takes from the user
+// program, not counting the NUL
+const maxStringLength = 1 << 16
 
 func advanceCounters() {
 	// Advance program counters.
@@ -29,109 +25,74 @@ func advanceCounters() {
 	global.Machine.WriteRegister(machine.NextPCReg, global.Machine.ReadRegister(machine.NextPCReg)+4)
 }
 
-func convertIntToHex(v uint32, console interfaces.IConsole) {
+// exitProcess ends the current process with status "exitCode".  Its memory
+// is given back, its files are closed, and its thread finishes; if it was
+// the last process, the machine halts.
+func exitProcess(exitCode int) {
+	processTable.OpenFiles(global.CurrentThread.PID()).CloseAll()
+	global.CurrentThread.Space().Release()
+	global.CurrentThread.SetSpace(nil)
+	if processTable.Exit(global.CurrentThread.PID(), exitCode) == 0 {
+		utils.Debug('a', "Last process exited, shutting down.\n")
+		global.Interrupt.Halt()
+	}
+	global.CurrentThread.FinishThread()
+}
+
+// killProcess ends the current process because of exception "which",
+// which the program cannot recover from.  Only the process dies, with
+// exit status -1; the rest of the system keeps running.
+func killProcess(which enums.ExceptionType) {
+	fmt.Printf("Process %d killed by %s exception at PC 0x%x, bad virtual address 0x%x\n",
+		global.CurrentThread.PID(), which, global.Machine.ReadRegister(machine.PCReg),
+		global.Machine.ReadRegister(machine.BadVAddrReg))
+	exitProcess(-1)
+}
+
+func convertIntToHex(v uint32, console interfaces.ISynchConsole) {
 	if v == 0 {
 		return
 	}
 	convertIntToHex(v/16, console)
 	x := v % 16
 	if x < 10 {
-		writeDone.P()
 		console.PutChar(byte('0' + x))
 	} else {
-		writeDone.P()
 		console.PutChar(byte('a' + x - 10))
 	}
 
 }
 
-func writeDoneFunc(interface{}) {
-	writeDone.V()
-}
-
-func readAvailFunc(interface{}) {
-	readAvail.V()
-}
-
 // Init the exception handler
 func Init() {
-	global.ExceptionHandler = func(which enums.ExceptionType) {
-		typeSyscall := global.Machine.ReadRegister(2)
-		// int memval, vaddr, printval, tempval, exp;
-		// unsigned printvalus;        // Used for printing in hex
-		if !initializedConsoleSemaphores {
-			readAvail = &synch.Semaphore{}
-			readAvail.Init("read avail", 0)
-			writeDone = &synch.Semaphore{}
-			writeDone.Init("write done", 1)
-			initializedConsoleSemaphores = true
-		}
-		var console interfaces.IConsole = &console.Console{}
-		console.Init("", "", readAvailFunc, writeDoneFunc, 0)
+	processTable.Init()
+	frameAllocator.Init(machine.NumPhysPages)
+	pagingLock = &synch.Lock{}
+	pagingLock.Init("paging lock")
+	registerSyscalls()
 
-		if which == enums.SyscallException {
-			switch typeSyscall {
-			case C.SysCall_Halt:
-				utils.Debug('a', "Shutdown, initiated by user program.\n")
-				global.Interrupt.Halt()
-			case C.SysCall_PrintInt:
-				printval := int32(global.Machine.ReadRegister(4))
-				if printval == 0 {
-					writeDone.P()
-					console.PutChar('0')
-				} else {
-					if printval < 0 {
-						writeDone.P()
-						console.PutChar('-')
-						printval = -printval
-					}
-					tempval := printval
-					exp := int32(1)
-					for tempval != 0 {
-						tempval = tempval / 10
-						exp = exp * 10
-					}
-					exp = exp / 10
-					for exp > 0 {
-						writeDone.P()
-						console.PutChar(byte('0' + (printval / exp)))
-						printval = printval % exp
-						exp = exp / 10
-					}
-				}
-				advanceCounters()
-			case C.SysCall_PrintChar:
-				writeDone.P()
-				console.PutChar(byte(global.Machine.ReadRegister(4))) // echo it!
-				advanceCounters()
-			case C.SysCall_PrintString:
-				vaddr := global.Machine.ReadRegister(4)
-				memval, _ := global.Machine.ReadMem(vaddr, 1)
-				for memval != 0 {
-					writeDone.P()
-					console.PutChar(byte(memval))
-					vaddr++
-					memval, _ = global.Machine.ReadMem(vaddr, 1)
-				}
-				advanceCounters()
-			case C.SysCall_PrintIntHex:
-				printval := global.Machine.ReadRegister(4)
-				writeDone.P()
-				console.PutChar('0')
-				writeDone.P()
-				console.PutChar('x')
-				if printval == 0 {
-					writeDone.P()
-					console.PutChar('0')
-				} else {
-					convertIntToHex(printval, console)
-				}
-			default:
-				fmt.Printf("Unexpected user mode exception %q %v\n", which, typeSyscall)
-				utils.Assert(false, "Unsupported type of syscall")
+	global.ExceptionHandler = func(which enums.ExceptionType) {
+		switch which {
+		case enums.SyscallException:
+			dispatchSyscall()
+		case enums.PageFaultException:
+			// Bring the page in; the faulting instruction is then restarted
+			badVAddr := global.Machine.ReadRegister(machine.BadVAddrReg)
+			if !global.CurrentThread.Space().HandlePageFault(badVAddr) {
+				killProcess(which)
+			}
+		case enums.ReadOnlyException:
+			// Fine if the page is only shared copy-on-write; the write is
+			// then restarted on a copy of the page
+			badVAddr := global.Machine.ReadRegister(machine.BadVAddrReg)
+			if !global.CurrentThread.Space().HandleCopyOnWrite(badVAddr) {
+				killProcess(which)
 			}
-		} else {
-			fmt.Printf("Unexpected user mode exception %q %v\n", which, typeSyscall)
+		case enums.BusErrorException, enums.AddressErrorException,
+			enums.OverflowException, enums.IllegalInstrException:
+			killProcess(which)
+		default:
+			fmt.Printf("Unexpected user mode exception %q\n", which)
 			utils.Assert(false, "Unsupported type of exception")
 		}
 	}
@@ -0,0 +1,145 @@
+package userprog
+
+import (
+	"github.com/yashsriv/go-nachos/global"
+	"github.com/yashsriv/go-nachos/interfaces"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// Init initializes the allocator with "numFrames" free frames
+func (fa *FrameAllocator) Init(numFrames uint32) {
+	fa.numFrames = numFrames
+	fa.frames = &utils.BitMap{}
+	fa.frames.Init(int(numFrames))
+	fa.owners = make([][]frameOwner, numFrames)
+	fa.SetPolicy(&FIFOPolicy{})
+	fa.updateStats()
+}
+
+// SetPolicy sets the page replacement policy used by Evict
+func (fa *FrameAllocator) SetPolicy(policy interfaces.IReplacementPolicy) {
+	policy.Init(fa.numFrames)
+	fa.policy = policy
+	global.Stats.PageReplacementPolicy = policy.Name()
+}
+
+// Allocate finds a free frame and marks it as holding virtual page "vpn"
+// of "space".
+//
+// Returns false if all of physical memory is in use.
+func (fa *FrameAllocator) Allocate(space *ProcessAddressSpace, vpn uint32) (uint32, bool) {
+	frame := fa.frames.Find()
+	if frame == -1 {
+		utils.Debug('a', "No free physical frames left\n")
+		return 0, false
+	}
+	utils.Debug('a', "Allocated physical frame %d\n", frame)
+	fa.owners[frame] = []frameOwner{{space, vpn}}
+	fa.policy.FrameLoaded(uint32(frame))
+	fa.updateStats()
+	return uint32(frame), true
+}
+
+// Share marks "frame" as also holding virtual page "vpn" of "space".  The
+// frame stays in use until every owner has freed it.
+func (fa *FrameAllocator) Share(frame uint32, space *ProcessAddressSpace, vpn uint32) {
+	utils.Assert(fa.frames.Test(int(frame)), "Only a frame in use can be shared")
+	fa.owners[frame] = append(fa.owners[frame], frameOwner{space, vpn})
+	global.Stats.NumCOWFramesSaved++
+}
+
+// Free gives up virtual page "vpn" of "space"'s hold on "frame".  Once
+// nobody holds it, the frame goes back to the pool of free frames.
+func (fa *FrameAllocator) Free(frame uint32, space *ProcessAddressSpace, vpn uint32) {
+	utils.Assert(fa.frames.Test(int(frame)), "Only a frame in use can be freed")
+	owners, found := fa.owners[frame], false
+	for i := range owners {
+		if owners[i] == (frameOwner{space, vpn}) {
+			fa.owners[frame] = append(owners[:i], owners[i+1:]...)
+			found = true
+			break
+		}
+	}
+	utils.Assert(found, "Only an owner of a frame can free it")
+	if len(fa.owners[frame]) > 0 {
+		return
+	}
+	utils.Debug('a', "Freeing physical frame %d\n", frame)
+	fa.frames.Clear(int(frame))
+	fa.owners[frame] = nil
+	fa.policy.FrameFreed(frame)
+	fa.updateStats()
+}
+
+// Evict makes room in main memory, by asking the replacement policy for a
+// frame and paging out whatever is in it.  A shared frame is paged out
+// of every address space which holds it.
+func (fa *FrameAllocator) Evict() {
+	syncTLB() // the policy goes by the use bits in the page tables
+	frame := fa.policy.SelectVictim()
+	owners := append([]frameOwner(nil), fa.owners[frame]...)
+	utils.Assert(len(owners) > 0, "The victim frame should be in use")
+	global.Stats.CountPageReplacement()
+	for _, owner := range owners {
+		utils.Debug('a', "Evicting virtual page %d from frame %d\n", owner.vpn, frame)
+		owner.space.pageOut(owner.vpn)
+	}
+}
+
+// SampleUse lets the replacement policy look at which frames have been
+// referenced lately.  Called on every timer interrupt.
+func (fa *FrameAllocator) SampleUse() {
+	syncTLB() // the policy goes by the use bits in the page tables
+	fa.policy.SampleUse()
+}
+
+// InUse checks whether "frame" holds some page
+func (fa *FrameAllocator) InUse(frame uint32) bool {
+	return fa.frames.Test(int(frame))
+}
+
+// RefCount returns the number of virtual pages which share "frame"
+func (fa *FrameAllocator) RefCount(frame uint32) int {
+	return len(fa.owners[frame])
+}
+
+// IsUsed checks whether "frame", which must be in use, has been referenced
+// through any of the page table entries mapping to it.
+func (fa *FrameAllocator) IsUsed(frame uint32) bool {
+	for _, owner := range fa.owners[frame] {
+		if owner.space.kernelPageTable[owner.vpn].Use {
+			return true
+		}
+	}
+	return false
+}
+
+// ClearUse clears the use bit of every page table entry mapping to "frame"
+func (fa *FrameAllocator) ClearUse(frame uint32) {
+	for _, owner := range fa.owners[frame] {
+		owner.space.kernelPageTable[owner.vpn].Use = false
+	}
+}
+
+// IsDirty checks whether "frame", which must be in use, would have to be
+// saved to be evicted.
+func (fa *FrameAllocator) IsDirty(frame uint32) bool {
+	for _, owner := range fa.owners[frame] {
+		if owner.space.kernelPageTable[owner.vpn].Dirty {
+			return true
+		}
+	}
+	return false
+}
+
+// NumFree returns the number of frames which are not in use
+func (fa *FrameAllocator) NumFree() uint32 {
+	return uint32(fa.frames.NumClear())
+}
+
+// updateStats reflects the current usage of main memory in the statistics
+func (fa *FrameAllocator) updateStats() {
+	numFree := fa.frames.NumClear()
+	global.Stats.NumFreeFrames = numFree
+	global.Stats.NumUsedFrames = int(fa.numFrames) - numFree
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package userprog
+
+import (
+	"github.com/yashsriv/go-nachos/interfaces"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// frameOwner records which virtual page of which address space is held
+// in a physical frame
+type frameOwner struct {
+	space *ProcessAddressSpace
+	vpn   uint32
+}
+
+// FrameAllocator keeps track of which physical page frames of main memory
+// are in use by some address space and which are free.
+//
+// Frames are handed out one virtual page at a time, so the frames of an
+// address space need not be contiguous, and they are given back when the
+// address space is released.
+//
+// The allocator also remembers the owners of every frame in use (a "core
+// map"), so that when memory runs out the replacement policy can pick a
+// frame and the page in it can be evicted.  A frame has more than one
+// owner when processes share it after a fork, until they write to it.
+type FrameAllocator struct {
+	frames    *utils.BitMap // a set bit means the frame is in use
+	numFrames uint32
+	owners    [][]frameOwner
+
+	policy interfaces.IReplacementPolicy // decides which frame to evict
+}
+
+// frameAllocator is the allocator for the frames of main memory
+var frameAllocator = &FrameAllocator{}
+
+// Implemented in frame-allocator-impl.go
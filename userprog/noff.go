@@ -13,6 +13,17 @@ type Segment struct {
 	Size        uint32 // size of segment
 }
 
+// fitsIn checks that the segment lies within an address space of "size" bytes
+func (s Segment) fitsIn(size uint32) bool {
+	return s.Size == 0 || (s.VirtualAddr <= size && s.Size <= size-s.VirtualAddr)
+}
+
+// covers checks whether all of the addresses from "start" up to "end" lie
+// within the segment
+func (s Segment) covers(start uint32, end uint32) bool {
+	return s.Size > 0 && s.VirtualAddr <= start && end <= s.VirtualAddr+s.Size
+}
+
 // NoffHeader contains info about the file
 type NoffHeader struct {
 	NoffMagic  uint32  // should be NOFFMAGIC
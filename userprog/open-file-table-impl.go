@@ -0,0 +1,222 @@
+package userprog
+
+import (
+	"github.com/yashsriv/go-nachos/global"
+	"github.com/yashsriv/go-nachos/interfaces"
+	"github.com/yashsriv/go-nachos/threads/synch"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// newOpenFile returns an openFile for "file", at its beginning, with no
+// OpenFileIds referring to it yet.  A nil "file" stands for the console
+// device "console".
+func newOpenFile(file interfaces.IOpenFile, console int) *openFile {
+	lock := &synch.Lock{}
+	lock.Init("open file lock")
+	return &openFile{file: file, console: console, lock: lock}
+}
+
+// Init initializes a table with only the console open
+func (ft *OpenFileTable) Init() {
+	ft.files = [MaxOpenFiles]*openFile{}
+	ft.files[ConsoleInput] = newOpenFile(nil, ConsoleInput)
+	ft.files[ConsoleInput].refCount++
+	ft.files[ConsoleOutput] = newOpenFile(nil, ConsoleOutput)
+	ft.files[ConsoleOutput].refCount++
+}
+
+// InitFrom initializes a table for a child process, with the same open
+// files as its parent's table "parent", positions shared.
+func (ft *OpenFileTable) InitFrom(parent *OpenFileTable) {
+	ft.files = parent.files
+	for _, of := range ft.files {
+		if of != nil {
+			of.refCount++
+		}
+	}
+}
+
+// freeID returns the lowest OpenFileId in use neither by the console nor
+// by some open file, or -1 if the table is full
+func (ft *OpenFileTable) freeID() int {
+	for id := range ft.files {
+		if id != ConsoleInput && id != ConsoleOutput && ft.files[id] == nil {
+			return id
+		}
+	}
+	utils.Debug('a', "Too many open files\n")
+	return -1
+}
+
+// Add puts "file" in the table.
+//
+// Returns the ID it gets, or -1 if too many files are open.
+func (ft *OpenFileTable) Add(file interfaces.IOpenFile) int {
+	id := ft.freeID()
+	if id == -1 {
+		return -1
+	}
+	ft.files[id] = newOpenFile(file, 0)
+	ft.files[id].refCount++
+	return id
+}
+
+// lookup returns the file open as "id", or nil if there is none
+func (ft *OpenFileTable) lookup(id int) *openFile {
+	if id < 0 || id >= len(ft.files) {
+		return nil
+	}
+	return ft.files[id]
+}
+
+// Dup makes a new ID for the file open as "id", which shares its
+// position.
+//
+// Returns the new ID, or -1 if "id" is not open or too many files are
+// open.
+func (ft *OpenFileTable) Dup(id int) int {
+	of := ft.lookup(id)
+	if of == nil {
+		return -1
+	}
+	newID := ft.freeID()
+	if newID == -1 {
+		return -1
+	}
+	ft.files[newID] = of
+	of.refCount++
+	return newID
+}
+
+// Close takes "id" out of the table, and closes the file if no other ID
+// refers to it.
+//
+// Returns false if "id" is not open.
+func (ft *OpenFileTable) Close(id int) bool {
+	of := ft.lookup(id)
+	if of == nil {
+		return false
+	}
+	ft.files[id] = nil
+	of.refCount--
+	if of.refCount == 0 && of.file != nil {
+		of.file.Close()
+	}
+	return true
+}
+
+// CloseAll closes every file in the table, as the process is done with
+// them
+func (ft *OpenFileTable) CloseAll() {
+	for id := range ft.files {
+		ft.Close(id)
+	}
+}
+
+// Read reads up to "size" bytes from the file open as "id", and moves its
+// position past them.  Reading from the console waits for the whole
+// count, for the end of a line, or for the end of the input.
+//
+// Returns false if "id" is not open, or is not open for reading.
+func (ft *OpenFileTable) Read(id int, size int) ([]byte, bool) {
+	of := ft.lookup(id)
+	if of == nil || (of.file == nil && of.console != ConsoleInput) {
+		return nil, false
+	}
+	if of.file == nil {
+		var buf []byte
+		for len(buf) < size {
+			ch, err := global.SynchConsole.GetChar()
+			if err != nil {
+				break // no more input
+			}
+			buf = append(buf, ch)
+			if ch == '\n' {
+				break
+			}
+		}
+		return buf, true
+	}
+
+	of.lock.Acquire()
+	defer of.lock.Release()
+	if left := of.file.Length() - of.position; size > left {
+		size = left // can't read more than there is
+	}
+	if size <= 0 {
+		return nil, true
+	}
+	buf := make([]byte, size)
+	n := of.file.ReadAt(buf, of.position)
+	of.position += n
+	return buf[:n], true
+}
+
+// Write writes "buf" to the file open as "id", and moves its position
+// past it.
+//
+// Returns the number of bytes written, or false if "id" is not open, or
+// is not open for writing.
+func (ft *OpenFileTable) Write(id int, buf []byte) (int, bool) {
+	of := ft.lookup(id)
+	if of == nil || (of.file == nil && of.console != ConsoleOutput) {
+		return 0, false
+	}
+	if of.file == nil {
+		for _, ch := range buf {
+			global.SynchConsole.PutChar(ch)
+		}
+		return len(buf), true
+	}
+
+	of.lock.Acquire()
+	defer of.lock.Release()
+	n := of.file.WriteAt(buf, of.position)
+	of.position += n
+	return n, true
+}
+
+// Seek moves the position of the file open as "id" to "offset" bytes from
+// "whence" -- SeekSet, SeekCur or SeekEnd.
+//
+// Returns the new position, or -1 if "id" is not an open file, or the
+// position would be negative.
+func (ft *OpenFileTable) Seek(id int, offset int, whence int) int {
+	of := ft.lookup(id)
+	if of == nil || of.file == nil {
+		return -1
+	}
+	of.lock.Acquire()
+	defer of.lock.Release()
+	var position int
+	switch whence {
+	case SeekSet:
+		position = offset
+	case SeekCur:
+		position = of.position + offset
+	case SeekEnd:
+		position = of.file.Length() + offset
+	default:
+		return -1
+	}
+	if position < 0 {
+		return -1
+	}
+	of.position = position
+	return position
+}
+
+// Stat returns the size, type and number of sectors of the file open as
+// "id".
+//
+// Returns false if "id" is not open.
+func (ft *OpenFileTable) Stat(id int) (size int, fileType int, numSectors int, ok bool) {
+	of := ft.lookup(id)
+	if of == nil {
+		return 0, 0, 0, false
+	}
+	if of.file == nil {
+		return 0, FileTypeConsole, 0, true
+	}
+	return of.file.Length(), FileTypeRegular, of.file.NumSectors(), true
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package userprog
+
+import "github.com/yashsriv/go-nachos/interfaces"
+
+// openFile is a file opened by a process, or the console, together with
+// the position reads and writes start at.
+//
+// Several OpenFileIds can refer to the same openFile -- those made by
+// Dup, and those a child inherits from its parent in Fork -- and they
+// all move the same position.  The file is closed once none of them is
+// left.
+type openFile struct {
+	file     interfaces.IOpenFile // nil for the console
+	console  int                  // ConsoleInput or ConsoleOutput, if file is nil
+	position int                  // where the next read or write starts
+	refCount int                  // number of OpenFileIds referring to it
+	lock     interfaces.ILock     // makes reading or writing and moving the position atomic
+}
+
+// OpenFileTable holds the files a process has open, under the
+// OpenFileIds the process knows them by.  A process can have at most
+// MaxOpenFiles files open.
+//
+// A process starts out with ConsoleInput and ConsoleOutput open.  Those
+// two IDs are kept for the console; the files the process opens get the
+// other IDs, lowest first.
+type OpenFileTable struct {
+	files [MaxOpenFiles]*openFile
+}
+
+// Implemented in open-file-table-impl.go
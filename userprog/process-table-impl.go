@@ -0,0 +1,116 @@
+package userprog
+
+import (
+	"fmt"
+
+	"github.com/yashsriv/go-nachos/threads"
+	"github.com/yashsriv/go-nachos/threads/synch"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// Init initializes an empty process table
+func (pt *ProcessTable) Init() {
+	pt.entries = make(map[int]*processEntry)
+	pt.numRunning = 0
+}
+
+// Add records a newly created process "pid" whose parent is "ppid", and
+// which has the files in "files" open.
+func (pt *ProcessTable) Add(pid int, ppid int, files *OpenFileTable) {
+	utils.Assert(pt.entries[pid] == nil, "PID should not already be in the process table")
+	utils.Debug('a', "Adding process %d with parent %d\n", pid, ppid)
+
+	exitSem := &synch.Semaphore{}
+	exitSem.Init(fmt.Sprintf("exit %d", pid), 0)
+	threads.HoldPID(pid) // not to be reused while it is in the table
+	pt.entries[pid] = &processEntry{
+		pid:      pid,
+		ppid:     ppid,
+		children: make(map[int]bool),
+		exitSem:  exitSem,
+		files:    files,
+	}
+	if parent, ok := pt.entries[ppid]; ok && !parent.exited {
+		parent.children[pid] = true
+	}
+	pt.numRunning++
+}
+
+// Exit records that process "pid" is done with status "exitCode", and
+// wakes up its parent if it is waiting in Join.
+//
+// Returns the number of processes which are still running.  When this
+// drops to zero there is nothing left for the machine to do.
+func (pt *ProcessTable) Exit(pid int, exitCode int) int {
+	entry := pt.entries[pid]
+	utils.Assert(entry != nil && !entry.exited, "Only a running process can exit")
+	utils.Debug('a', "Process %d exiting with code %d\n", pid, exitCode)
+
+	entry.exitCode = exitCode
+	entry.exited = true
+	pt.numRunning--
+
+	// Nobody can join our children any more, so forget the ones which
+	// are already done; the rest are cleaned up when they exit.
+	for child := range entry.children {
+		if pt.entries[child].exited {
+			pt.remove(child)
+		}
+	}
+	entry.children = nil
+
+	// A parent waiting in Join removes the entry itself
+	if !entry.joining && !pt.isJoinable(entry) {
+		pt.remove(pid)
+	}
+
+	entry.exitSem.V()
+	return pt.numRunning
+}
+
+// Join waits for process "child" to exit and returns its exit code.
+// Only the parent of a process may join it, and only once.
+//
+// Returns false if "child" is not an unjoined child of "parent".
+func (pt *ProcessTable) Join(parent int, child int) (int, bool) {
+	entry, ok := pt.entries[child]
+	if !ok || entry.joining || !pt.isJoinable(entry) || entry.ppid != parent {
+		utils.Debug('a', "Process %d cannot join %d\n", parent, child)
+		return 0, false
+	}
+	// The child stays joinable while we wait, so that it leaves its
+	// entry to us when it exits
+	entry.joining = true
+	entry.exitSem.P() // returns at once if the child is already done
+
+	// The child could only be joined once
+	delete(pt.entries[parent].children, child)
+	pt.remove(child)
+	utils.Debug('a', "Process %d joined %d, exit code %d\n", parent, child, entry.exitCode)
+	return entry.exitCode, true
+}
+
+// OpenFiles returns the table of files process "pid" has open
+func (pt *ProcessTable) OpenFiles(pid int) *OpenFileTable {
+	entry := pt.entries[pid]
+	utils.Assert(entry != nil && !entry.exited, "Only a running process has open files")
+	return entry.files
+}
+
+// NumRunning returns the number of processes which have not exited yet
+func (pt *ProcessTable) NumRunning() int {
+	return pt.numRunning
+}
+
+// remove drops the entry of process "pid", whose PID may then be reused
+func (pt *ProcessTable) remove(pid int) {
+	delete(pt.entries, pid)
+	threads.ReleasePID(pid)
+}
+
+// isJoinable checks whether the parent of a process is still around to
+// join it.
+func (pt *ProcessTable) isJoinable(entry *processEntry) bool {
+	parent, ok := pt.entries[entry.ppid]
+	return ok && !parent.exited && parent.children[entry.pid]
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package userprog
+
+import "github.com/yashsriv/go-nachos/interfaces"
+
+// processEntry is the kernel's record of a single user process. It outlives
+// the process itself so that the parent can collect the exit code in Join.
+type processEntry struct {
+	pid      int
+	ppid     int
+	exitCode int
+	exited   bool
+	joining  bool                  // the parent is waiting in Join
+	children map[int]bool          // PIDs of children which have not been joined
+	exitSem  interfaces.ISemaphore // V'ed once when the process exits
+	files    *OpenFileTable        // files the process has open
+}
+
+// ProcessTable keeps track of every user process in the system -- its
+// parent, its children and, once it is done, its exit code.
+//
+// A parent calling Join on a child blocks on the child's semaphore until
+// the child calls Exit.  Entries are reclaimed once they are joined, or
+// as soon as nobody is left who could join them.
+type ProcessTable struct {
+	entries    map[int]*processEntry
+	numRunning int // number of processes that have not exited yet
+}
+
+// processTable is the kernel's process table
+var processTable = &ProcessTable{}
+
+// Implemented in process-table-impl.go
@@ -0,0 +1,96 @@
+package userprog
+
+import (
+	"testing"
+
+	"github.com/yashsriv/go-nachos/enums"
+	"github.com/yashsriv/go-nachos/global"
+	"github.com/yashsriv/go-nachos/machine"
+	"github.com/yashsriv/go-nachos/threads"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// startNachos sets up the machine as nachos does with "-rs seed", and
+// makes the caller the main thread
+func startNachos(seed int64) {
+	utils.RandomInit(seed)
+
+	global.Stats = utils.Statistics{}
+
+	global.Interrupt = &machine.Interrupt{}
+	global.Interrupt.Init()
+
+	global.Scheduler = &threads.Scheduler{}
+	global.Scheduler.Init()
+
+	global.Timer = &machine.Timer{}
+	global.Timer.Init(func(arg interface{}) {
+		global.Scheduler.WakeSleepingThreads()
+		if global.Interrupt.GetStatus() != enums.IdleMode {
+			global.Interrupt.YieldOnReturn()
+		}
+	}, nil, true)
+
+	global.ThreadToBeDestroyed = nil
+	global.CurrentThread = &threads.Thread{}
+	global.CurrentThread.Init("main")
+	global.CurrentThread.SetStatus(enums.Running)
+	global.Interrupt.Enable()
+}
+
+// forkChild starts a child of the current thread in "pt", which lets some
+// time pass and exits with "exitCode"
+func forkChild(pt *ProcessTable, exitCode int) int {
+	child := &threads.Thread{}
+	child.Init("child")
+	pt.Add(child.PID(), child.PPID(), nil)
+	child.ThreadFork(func(arg interface{}) {
+		for i := 0; i < 50; i++ {
+			global.Interrupt.SetLevel(enums.IntOff)
+			global.Interrupt.SetLevel(enums.IntOn)
+		}
+		pt.Exit(global.CurrentThread.PID(), arg.(int))
+	}, exitCode)
+	return child.PID()
+}
+
+func TestProcessTableJoinRunningChild(t *testing.T) {
+	startNachos(1)
+	pt := &ProcessTable{}
+	pt.Init()
+	parent := global.CurrentThread.PID()
+	pt.Add(parent, threads.NO_PARENT, nil)
+
+	child := forkChild(pt, 7)
+	// The child has not run yet, so Join has to wait for it
+	exitCode, ok := pt.Join(parent, child)
+	if !ok || exitCode != 7 {
+		t.Errorf("Join returned %d, %v, want 7, true", exitCode, ok)
+	}
+	if _, ok := pt.entries[child]; ok {
+		t.Errorf("child %d is still in the process table", child)
+	}
+	if _, ok := pt.Join(parent, child); ok {
+		t.Errorf("child %d could be joined twice", child)
+	}
+}
+
+func TestProcessTableJoinExitedChild(t *testing.T) {
+	startNachos(1)
+	pt := &ProcessTable{}
+	pt.Init()
+	parent := global.CurrentThread.PID()
+	pt.Add(parent, threads.NO_PARENT, nil)
+
+	child := forkChild(pt, 3)
+	for !pt.entries[child].exited {
+		global.CurrentThread.YieldCPU()
+	}
+	exitCode, ok := pt.Join(parent, child)
+	if !ok || exitCode != 3 {
+		t.Errorf("Join returned %d, %v, want 3, true", exitCode, ok)
+	}
+	if _, ok := pt.entries[child]; ok {
+		t.Errorf("child %d is still in the process table", child)
+	}
+}
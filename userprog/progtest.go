@@ -12,9 +12,14 @@ import (
 //	memory, and jump to it.
 func LaunchUserProcess(filename string) {
 	var space = &ProcessAddressSpace{}
-	space.Init(filename)
+	if err := space.Init(filename); err != nil {
+		utils.Panic(err)
+	}
 
 	global.CurrentThread.SetSpace(space)
+	files := &OpenFileTable{}
+	files.Init()
+	processTable.Add(global.CurrentThread.PID(), global.CurrentThread.PPID(), files)
 
 	space.InitUserModeCPURegisters() // set the initial register values
 	space.RestoreContextOnSwitch()   // load page table register
@@ -28,10 +33,6 @@ func LaunchUserProcess(filename string) {
 var forkFunction = func(arg interface{}) {
 	utils.Debug('t', "Now in thread %q\n", global.CurrentThread)
 
-	if global.ThreadToBeDestroyed != nil {
-		global.ThreadToBeDestroyed = nil
-	}
-
 	if global.CurrentThread.Space() != nil { // if there is an address space
 		global.CurrentThread.RestoreUserState() // to restore, do it.
 		global.CurrentThread.Space().RestoreContextOnSwitch()
@@ -0,0 +1,206 @@
+package userprog
+
+import (
+	"container/list"
+	"fmt"
+
+	"github.com/yashsriv/go-nachos/interfaces"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// SetReplacementPolicy selects the page replacement policy by name --
+// one of "fifo", "random", "lru" or "clock".
+func SetReplacementPolicy(name string) error {
+	var policy interfaces.IReplacementPolicy
+	switch name {
+	case "fifo":
+		policy = &FIFOPolicy{}
+	case "random":
+		policy = &RandomPolicy{}
+	case "lru":
+		policy = &LRUPolicy{}
+	case "clock":
+		policy = &ClockPolicy{}
+	default:
+		return fmt.Errorf("Unknown page replacement policy %q", name)
+	}
+	frameAllocator.SetPolicy(policy)
+	return nil
+}
+
+// SampleFrameUse lets the page replacement policy look at the use bits
+// of the frames.  To be called on every timer interrupt.
+func SampleFrameUse() {
+	frameAllocator.SampleUse()
+}
+
+// Init initializes the FIFO queue
+func (p *FIFOPolicy) Init(numFrames uint32) {
+	p.queue = list.New()
+	p.elements = make(map[uint32]*list.Element)
+}
+
+// Name returns the name of the policy
+func (p *FIFOPolicy) Name() string {
+	return "fifo"
+}
+
+// FrameLoaded puts "frame" at the end of the queue
+func (p *FIFOPolicy) FrameLoaded(frame uint32) {
+	p.elements[frame] = p.queue.PushBack(frame)
+}
+
+// FrameFreed takes "frame" out of the queue
+func (p *FIFOPolicy) FrameFreed(frame uint32) {
+	p.queue.Remove(p.elements[frame])
+	delete(p.elements, frame)
+}
+
+// SampleUse does nothing, FIFO does not care about references
+func (p *FIFOPolicy) SampleUse() {}
+
+// SelectVictim returns the frame at the head of the queue
+func (p *FIFOPolicy) SelectVictim() uint32 {
+	utils.Assert(p.queue.Front() != nil, "There should be a frame in use to evict")
+	return p.queue.Front().Value.(uint32)
+}
+
+// Init initializes the random policy
+func (p *RandomPolicy) Init(numFrames uint32) {
+	p.numFrames = numFrames
+}
+
+// Name returns the name of the policy
+func (p *RandomPolicy) Name() string {
+	return "random"
+}
+
+// FrameLoaded does nothing, the random policy keeps no history
+func (p *RandomPolicy) FrameLoaded(frame uint32) {}
+
+// FrameFreed does nothing, the random policy keeps no history
+func (p *RandomPolicy) FrameFreed(frame uint32) {}
+
+// SampleUse does nothing, the random policy keeps no history
+func (p *RandomPolicy) SampleUse() {}
+
+// SelectVictim returns a random frame in use
+func (p *RandomPolicy) SelectVictim() uint32 {
+	var candidates []uint32
+	for frame := uint32(0); frame < p.numFrames; frame++ {
+		if frameAllocator.InUse(frame) {
+			candidates = append(candidates, frame)
+		}
+	}
+	utils.Assert(len(candidates) > 0, "There should be a frame in use to evict")
+	return candidates[utils.Random()%len(candidates)]
+}
+
+// Init initializes the LRU policy
+func (p *LRUPolicy) Init(numFrames uint32) {
+	p.numFrames = numFrames
+	p.age = make([]uint32, numFrames)
+}
+
+// Name returns the name of the policy
+func (p *LRUPolicy) Name() string {
+	return "lru"
+}
+
+// FrameLoaded starts the history of "frame" afresh, as just referenced
+func (p *LRUPolicy) FrameLoaded(frame uint32) {
+	p.age[frame] = 1 << 31
+}
+
+// FrameFreed forgets the history of "frame"
+func (p *LRUPolicy) FrameFreed(frame uint32) {
+	p.age[frame] = 0
+}
+
+// SampleUse ages every frame in use, shifting in its use bit, and clears
+// the use bits for the next period
+func (p *LRUPolicy) SampleUse() {
+	for frame := uint32(0); frame < p.numFrames; frame++ {
+		if !frameAllocator.InUse(frame) {
+			continue
+		}
+		p.age[frame] >>= 1
+		if frameAllocator.IsUsed(frame) {
+			p.age[frame] |= 1 << 31
+			frameAllocator.ClearUse(frame)
+		}
+	}
+}
+
+// SelectVictim returns the frame in use which was referenced the longest
+// time ago.  Among equally old frames, a clean one is preferred since it
+// need not be saved.
+func (p *LRUPolicy) SelectVictim() uint32 {
+	p.SampleUse() // take the references since the last timer interrupt into account
+	victim, found := uint32(0), false
+	for frame := uint32(0); frame < p.numFrames; frame++ {
+		if !frameAllocator.InUse(frame) {
+			continue
+		}
+		if !found {
+			victim, found = frame, true
+			continue
+		}
+		if p.age[frame] < p.age[victim] ||
+			(p.age[frame] == p.age[victim] && frameAllocator.IsDirty(victim) && !frameAllocator.IsDirty(frame)) {
+			victim = frame
+		}
+	}
+	utils.Assert(found, "There should be a frame in use to evict")
+	return victim
+}
+
+// Init initializes the clock policy
+func (p *ClockPolicy) Init(numFrames uint32) {
+	p.numFrames = numFrames
+	p.hand = 0
+}
+
+// Name returns the name of the policy
+func (p *ClockPolicy) Name() string {
+	return "clock"
+}
+
+// FrameLoaded does nothing, the use bits are kept by the machine
+func (p *ClockPolicy) FrameLoaded(frame uint32) {}
+
+// FrameFreed does nothing, the use bits are kept by the machine
+func (p *ClockPolicy) FrameFreed(frame uint32) {}
+
+// SampleUse does nothing, the clock hand looks at the use bits itself
+func (p *ClockPolicy) SampleUse() {}
+
+// SelectVictim advances the clock hand until it finds a frame in use
+// whose use bit is clear.  A first sweep looks for a clean frame only,
+// without touching anything; a second sweep takes a dirty one as well,
+// and clears every use bit it passes.  If that fails too, every use bit
+// is clear by then, so the next two sweeps find a victim.
+func (p *ClockPolicy) SelectVictim() uint32 {
+	for round := 0; round < 2; round++ {
+		for i := uint32(0); i < p.numFrames; i++ {
+			frame := p.hand
+			p.hand = (p.hand + 1) % p.numFrames
+			if frameAllocator.InUse(frame) && !frameAllocator.IsUsed(frame) && !frameAllocator.IsDirty(frame) {
+				return frame
+			}
+		}
+		for i := uint32(0); i < p.numFrames; i++ {
+			frame := p.hand
+			p.hand = (p.hand + 1) % p.numFrames
+			if !frameAllocator.InUse(frame) {
+				continue
+			}
+			if !frameAllocator.IsUsed(frame) {
+				return frame
+			}
+			frameAllocator.ClearUse(frame) // second chance
+		}
+	}
+	utils.Assert(false, "There should be a frame in use to evict")
+	return 0
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package userprog
+
+import (
+	"container/list"
+
+	"github.com/yashsriv/go-nachos/interfaces"
+)
+
+// FIFOPolicy evicts the page which was brought into memory the earliest
+type FIFOPolicy struct {
+	queue    *list.List               // frames, in the order they were loaded
+	elements map[uint32]*list.Element // position of each frame in the queue
+}
+
+// RandomPolicy evicts a page chosen at random.  The choice is deterministic
+// for a given seed passed with -rs.
+type RandomPolicy struct {
+	numFrames uint32
+}
+
+// LRUPolicy evicts the page which was referenced the longest time ago.
+//
+// The machine only keeps a use bit per page, so the time of the last
+// reference is approximated by aging: on every timer interrupt each
+// frame's age is shifted right, the use bit is shifted in at the top and
+// then cleared.  The frame with the smallest age was referenced the
+// longest time ago.
+type LRUPolicy struct {
+	numFrames uint32
+	age       []uint32 // reference history of each frame, most recent in the top bit
+}
+
+// ClockPolicy gives every page a second chance -- the clock hand sweeps
+// over the frames and evicts the first page whose use bit is clear,
+// clearing the use bit of every page it passes.  Clean pages go first: a
+// page which is neither used nor dirty is preferred to one which is not
+// used but would have to be written to the swap area.
+type ClockPolicy struct {
+	numFrames uint32
+	hand      uint32
+}
+
+var _ interfaces.IReplacementPolicy = &FIFOPolicy{}
+var _ interfaces.IReplacementPolicy = &RandomPolicy{}
+var _ interfaces.IReplacementPolicy = &LRUPolicy{}
+var _ interfaces.IReplacementPolicy = &ClockPolicy{}
+
+// Implemented in replacement-impl.go
@@ -0,0 +1,386 @@
+package userprog
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/yashsriv/go-nachos/global"
+	"github.com/yashsriv/go-nachos/machine"
+	"github.com/yashsriv/go-nachos/threads"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// RegisterSyscall makes "handler" carry out system call number "num".
+// "name" is only used for debugging.  A handler registered later for the
+// same number replaces the earlier one.
+func RegisterSyscall(num int, name string, handler SyscallHandler) {
+	utils.Debug('a', "Registering system call %d (%s)\n", num, name)
+	syscallTable[num] = syscallEntry{name, handler}
+}
+
+// dispatchSyscall carries out the system call the user program asked for
+// in register 2, with its arguments in registers 4 to 7, and puts the
+// result in register 2.
+//
+// The program counter is advanced before the handler is called, so that
+// the user program goes on with the next instruction.
+func dispatchSyscall() {
+	for i := range syscallRegs {
+		syscallRegs[i] = global.Machine.ReadRegister(i)
+	}
+	num := int(syscallRegs[2])
+	var args SyscallArgs
+	copy(args[:], syscallRegs[4:])
+	advanceCounters()
+
+	result := -1
+	if entry, ok := syscallTable[num]; ok {
+		utils.Debug('a', "System call %s(0x%x, 0x%x, 0x%x, 0x%x)\n", entry.name, args[0], args[1], args[2], args[3])
+		result = entry.handler(args)
+	} else {
+		fmt.Printf("Unknown system call %d from process %d\n", num, global.CurrentThread.PID())
+	}
+	global.Machine.WriteRegister(2, uint32(int32(result)))
+}
+
+// registerSyscalls registers the handlers of the system calls implemented
+// in this package
+func registerSyscalls() {
+	RegisterSyscall(SyscallHalt, "Halt", sysHalt)
+	RegisterSyscall(SyscallExit, "Exit", sysExit)
+	RegisterSyscall(SyscallExec, "Exec", sysExec)
+	RegisterSyscall(SyscallJoin, "Join", sysJoin)
+	RegisterSyscall(SyscallCreate, "Create", sysCreate)
+	RegisterSyscall(SyscallOpen, "Open", sysOpen)
+	RegisterSyscall(SyscallRead, "Read", sysRead)
+	RegisterSyscall(SyscallWrite, "Write", sysWrite)
+	RegisterSyscall(SyscallClose, "Close", sysClose)
+	RegisterSyscall(SyscallDup, "Dup", sysDup)
+	RegisterSyscall(SyscallSeek, "Seek", sysSeek)
+	RegisterSyscall(SyscallStat, "Stat", sysStat)
+	RegisterSyscall(SyscallFork, "Fork", sysFork)
+	RegisterSyscall(SyscallPrintInt, "PrintInt", sysPrintInt)
+	RegisterSyscall(SyscallPrintChar, "PrintChar", sysPrintChar)
+	RegisterSyscall(SyscallPrintString, "PrintString", sysPrintString)
+	RegisterSyscall(SyscallPrintIntHex, "PrintIntHex", sysPrintIntHex)
+	RegisterSyscall(SyscallGetReg, "GetReg", sysGetReg)
+	RegisterSyscall(SyscallGetPA, "GetPA", sysGetPA)
+	RegisterSyscall(SyscallGetPID, "GetPID", sysGetPID)
+	RegisterSyscall(SyscallGetPPID, "GetPPID", sysGetPPID)
+	RegisterSyscall(SyscallTime, "Time", sysTime)
+	RegisterSyscall(SyscallNumInstr, "NumInstr", sysNumInstr)
+	RegisterSyscall(SyscallSleep, "Sleep", sysSleep)
+	RegisterSyscall(SyscallYield, "Yield", sysYield)
+}
+
+// sysHalt stops Nachos
+func sysHalt(args SyscallArgs) int {
+	utils.Debug('a', "Shutdown, initiated by user program.\n")
+	global.Interrupt.Halt()
+	return 0
+}
+
+// sysExit ends the current process with the status in the first argument
+func sysExit(args SyscallArgs) int {
+	exitProcess(int(int32(args[0])))
+	return 0
+}
+
+// sysJoin waits for the child whose PID is the first argument to exit,
+// and returns its exit status, or -1 if it is not a child which can be
+// joined
+func sysJoin(args SyscallArgs) int {
+	exitCode, ok := processTable.Join(global.CurrentThread.PID(), int(int32(args[0])))
+	if !ok {
+		return -1
+	}
+	return exitCode
+}
+
+// sysExec replaces the current program with the executable whose name is
+// the first argument.  Only returns, with -1, if the executable cannot be
+// loaded.
+func sysExec(args SyscallArgs) int {
+	filename, err := CopyInString(args[0], maxStringLength)
+	space := &ProcessAddressSpace{}
+	if err == nil {
+		err = space.Init(filename)
+	}
+	if err != nil {
+		utils.Debug('a', "Exec of %q failed: %v\n", filename, err)
+		return -1
+	}
+
+	global.CurrentThread.Space().Release()
+	global.CurrentThread.SetSpace(space)
+
+	space.InitUserModeCPURegisters() // set the initial register values
+	space.RestoreContextOnSwitch()   // load page table register
+
+	global.Machine.Run()                                      // jump to the new progam
+	utils.Assert(false, "Code should never return back here") // machine->Run never returns
+	return -1
+}
+
+// sysFork creates a child process, with a copy of the address space of the
+// current one, which goes on from the same point.  Returns the PID of the
+// child, or -1 if it could not be created; the child gets 0.
+func sysFork(args SyscallArgs) int {
+	childSpace := &ProcessAddressSpace{}
+	if err := childSpace.InitFrom(global.CurrentThread.Space()); err != nil {
+		utils.Debug('a', "Fork failed: %v\n", err)
+		return -1
+	}
+	child := &threads.Thread{}
+	child.Init("forked thread")
+	child.SetSpace(childSpace)
+
+	child.SaveUserState()    // duplicate the parent's registers,
+	child.ResetReturnValue() // except that fork returns 0 in the child
+	files := &OpenFileTable{}
+	files.InitFrom(processTable.OpenFiles(global.CurrentThread.PID()))
+	processTable.Add(child.PID(), child.PPID(), files)
+	child.ThreadFork(forkFunction, nil)
+
+	return child.PID()
+}
+
+// sysCreate creates an empty file whose name is the first argument.
+// Returns 0, or -1 if the file can't be created.
+func sysCreate(args SyscallArgs) int {
+	name, err := CopyInString(args[0], maxStringLength)
+	if err != nil {
+		utils.Debug('a', "Create failed: %v\n", err)
+		return -1
+	}
+	if !global.FileSystem.Create(name, 0) {
+		return -1
+	}
+	return 0
+}
+
+// sysOpen opens the file whose name is the first argument.  Returns the
+// OpenFileId of the file, or -1 if there is no such file or too many
+// files are open.
+func sysOpen(args SyscallArgs) int {
+	name, err := CopyInString(args[0], maxStringLength)
+	if err != nil {
+		utils.Debug('a', "Open failed: %v\n", err)
+		return -1
+	}
+	file := global.FileSystem.Open(name)
+	if file == nil {
+		return -1
+	}
+	id := processTable.OpenFiles(global.CurrentThread.PID()).Add(file)
+	if id == -1 {
+		file.Close()
+	}
+	return id
+}
+
+// sysRead reads up to the number of bytes in the second argument from the
+// open file in the third argument, into the buffer in the first.  Reading
+// from the console waits for the whole count, for the end of a line, or
+// for the end of the input.
+// Returns the number of bytes read, or -1.
+func sysRead(args SyscallArgs) int {
+	vaddr, size, id := args[0], int(int32(args[1])), int(int32(args[2]))
+	// Don't use up input we would have nowhere to put
+	if err := CheckWritable(vaddr, size); err != nil {
+		utils.Debug('a', "Read failed: %v\n", err)
+		return -1
+	}
+	buf, ok := processTable.OpenFiles(global.CurrentThread.PID()).Read(id, size)
+	if !ok {
+		return -1
+	}
+	if err := CopyOut(vaddr, buf); err != nil {
+		utils.Debug('a', "Read failed: %v\n", err)
+		return -1
+	}
+	return len(buf)
+}
+
+// sysWrite writes the number of bytes in the second argument from the
+// buffer in the first argument to the open file in the third.  Returns
+// the number of bytes written, or -1.
+func sysWrite(args SyscallArgs) int {
+	vaddr, size, id := args[0], int(int32(args[1])), int(int32(args[2]))
+	if size < 0 {
+		size = 0
+	}
+	// Fetch the whole buffer first, so that nothing is written if part of
+	// it is bad
+	buf, err := CopyIn(vaddr, size)
+	if err != nil {
+		utils.Debug('a', "Write failed: %v\n", err)
+		return -1
+	}
+	n, ok := processTable.OpenFiles(global.CurrentThread.PID()).Write(id, buf)
+	if !ok {
+		return -1
+	}
+	return n
+}
+
+// sysClose closes the open file in the first argument.  Returns 0, or -1
+// if there is no such open file.
+func sysClose(args SyscallArgs) int {
+	if !processTable.OpenFiles(global.CurrentThread.PID()).Close(int(int32(args[0]))) {
+		return -1
+	}
+	return 0
+}
+
+// sysDup returns a new OpenFileId for the open file in the first
+// argument, sharing its position, or -1
+func sysDup(args SyscallArgs) int {
+	return processTable.OpenFiles(global.CurrentThread.PID()).Dup(int(int32(args[0])))
+}
+
+// sysSeek moves the position in the open file in the first argument to
+// the offset in the second argument, counted from where the third says.
+// Returns the new position, or -1.
+func sysSeek(args SyscallArgs) int {
+	id, offset, whence := int(int32(args[0])), int(int32(args[1])), int(int32(args[2]))
+	return processTable.OpenFiles(global.CurrentThread.PID()).Seek(id, offset, whence)
+}
+
+// sysStat fills in the FileStat in the second argument for the open file
+// in the first.  Returns 0, or -1.
+func sysStat(args SyscallArgs) int {
+	size, fileType, numSectors, ok := processTable.OpenFiles(global.CurrentThread.PID()).Stat(int(int32(args[0])))
+	if !ok {
+		return -1
+	}
+	// The fields of a FileStat, in order
+	stat := make([]byte, 3*4)
+	binary.LittleEndian.PutUint32(stat[0:], uint32(size))
+	binary.LittleEndian.PutUint32(stat[4:], uint32(fileType))
+	binary.LittleEndian.PutUint32(stat[8:], uint32(numSectors))
+	if err := CopyOut(args[1], stat); err != nil {
+		utils.Debug('a', "Stat failed: %v\n", err)
+		return -1
+	}
+	return 0
+}
+
+// sysPrintInt prints the first argument in decimal
+func sysPrintInt(args SyscallArgs) int {
+	printval := int32(args[0])
+	if printval == 0 {
+		global.SynchConsole.PutChar('0')
+		return 0
+	}
+	if printval < 0 {
+		global.SynchConsole.PutChar('-')
+		printval = -printval
+	}
+	tempval := printval
+	exp := int32(1)
+	for tempval != 0 {
+		tempval = tempval / 10
+		exp = exp * 10
+	}
+	exp = exp / 10
+	for exp > 0 {
+		global.SynchConsole.PutChar(byte('0' + (printval / exp)))
+		printval = printval % exp
+		exp = exp / 10
+	}
+	return 0
+}
+
+// sysPrintChar prints the first argument as a character
+func sysPrintChar(args SyscallArgs) int {
+	global.SynchConsole.PutChar(byte(args[0])) // echo it!
+	return 0
+}
+
+// sysPrintString prints the string the first argument points to
+func sysPrintString(args SyscallArgs) int {
+	str, err := CopyInString(args[0], maxStringLength)
+	if err != nil {
+		utils.Debug('a', "PrintString failed: %v\n", err)
+		return -1
+	}
+	for i := 0; i < len(str); i++ {
+		global.SynchConsole.PutChar(str[i])
+	}
+	return 0
+}
+
+// sysPrintIntHex prints the first argument in hexadecimal
+func sysPrintIntHex(args SyscallArgs) int {
+	global.SynchConsole.PutChar('0')
+	global.SynchConsole.PutChar('x')
+	if args[0] == 0 {
+		global.SynchConsole.PutChar('0')
+	} else {
+		convertIntToHex(args[0], global.SynchConsole)
+	}
+	return 0
+}
+
+// sysSleep puts the current process to sleep for the number of ticks in
+// the first argument.  Sleeping for 0 ticks or less gives up the CPU to
+// whoever else is ready to run.
+func sysSleep(args SyscallArgs) int {
+	global.CurrentThread.Sleep(int(int32(args[0])))
+	return 0
+}
+
+// sysYield gives up the CPU to the next thread which is ready to run, if
+// any.  The user registers are saved on the switch, and restored when the
+// process gets the CPU back.
+func sysYield(args SyscallArgs) int {
+	global.CurrentThread.YieldCPU()
+	return 0
+}
+
+// sysGetReg returns the contents of the user register whose number is the
+// first argument, as they were when GetReg was called, or -1 if there is
+// no such register
+func sysGetReg(args SyscallArgs) int {
+	reg := int(int32(args[0]))
+	if reg < 0 || reg >= machine.NumTotalRegs {
+		return -1
+	}
+	return int(int32(syscallRegs[reg]))
+}
+
+// sysGetPA returns the physical address the virtual address in the first
+// argument translates to through the current page table, or -1 if it
+// does not translate to any (including if the page is not in memory)
+func sysGetPA(args SyscallArgs) int {
+	vaddr := args[0]
+	vpn, offset := vaddr/machine.PageSize, vaddr%machine.PageSize
+	pageTable := global.Machine.PageTable()
+	if vpn >= uint32(len(pageTable)) || !pageTable[vpn].Valid ||
+		pageTable[vpn].PhysicalPage >= machine.NumPhysPages {
+		return -1
+	}
+	return int(pageTable[vpn].PhysicalPage*machine.PageSize + offset)
+}
+
+// sysGetPID returns the PID of the current process
+func sysGetPID(args SyscallArgs) int {
+	return global.CurrentThread.PID()
+}
+
+// sysGetPPID returns the PID of the parent of the current process
+func sysGetPPID(args SyscallArgs) int {
+	return global.CurrentThread.PPID()
+}
+
+// sysTime returns the current simulated time, in ticks
+func sysTime(args SyscallArgs) int {
+	return global.Stats.TotalTicks
+}
+
+// sysNumInstr returns the number of user instructions the current process
+// has executed
+func sysNumInstr(args SyscallArgs) int {
+	return global.CurrentThread.NumInstr()
+}
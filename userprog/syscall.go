@@ -0,0 +1,90 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package userprog
+
+// #include "syscall.h"
+import "C"
+
+import "github.com/yashsriv/go-nachos/machine"
+
+// System call numbers, as defined in syscall.h.  The user program puts
+// one of these in register 2 before trapping into the kernel.
+const (
+	SyscallHalt        = C.SysCall_Halt
+	SyscallExit        = C.SysCall_Exit
+	SyscallExec        = C.SysCall_Exec
+	SyscallJoin        = C.SysCall_Join
+	SyscallCreate      = C.SysCall_Create
+	SyscallOpen        = C.SysCall_Open
+	SyscallRead        = C.SysCall_Read
+	SyscallWrite       = C.SysCall_Write
+	SyscallClose       = C.SysCall_Close
+	SyscallFork        = C.SysCall_Fork
+	SyscallYield       = C.SysCall_Yield
+	SyscallPrintInt    = C.SysCall_PrintInt
+	SyscallPrintChar   = C.SysCall_PrintChar
+	SyscallPrintString = C.SysCall_PrintString
+	SyscallGetReg      = C.SysCall_GetReg
+	SyscallGetPA       = C.SysCall_GetPA
+	SyscallGetPID      = C.SysCall_GetPID
+	SyscallGetPPID     = C.SysCall_GetPPID
+	SyscallSleep       = C.SysCall_Sleep
+	SyscallTime        = C.SysCall_Time
+	SyscallPrintIntHex = C.SysCall_PrintIntHex
+	SyscallDup         = C.SysCall_Dup
+	SyscallSeek        = C.SysCall_Seek
+	SyscallStat        = C.SysCall_Stat
+	SyscallNumInstr    = C.SysCall_NumInstr
+)
+
+// Open file IDs of the console, which every process starts out with
+const (
+	ConsoleInput  = C.ConsoleInput
+	ConsoleOutput = C.ConsoleOutput
+)
+
+// MaxOpenFiles is the most files a process can have open at the same
+// time, the console included
+const MaxOpenFiles = C.MaxOpenFiles
+
+// Where Seek counts the offset from
+const (
+	SeekSet = C.SeekSet
+	SeekCur = C.SeekCur
+	SeekEnd = C.SeekEnd
+)
+
+// Types of open files, as given by Stat
+const (
+	FileTypeRegular = C.FileTypeRegular
+	FileTypeConsole = C.FileTypeConsole
+)
+
+// SyscallArgs holds the arguments of a system call, the contents of
+// registers 4 to 7
+type SyscallArgs [4]uint32
+
+// SyscallHandler carries out a system call.  What it returns is handed
+// back to the user program in register 2.
+//
+// By the time the handler is called, the program counter has been moved
+// past the syscall instruction, so a handler which never returns (like
+// Exit) or which starts the user program over (like Exec) need not worry
+// about it.
+type SyscallHandler func(args SyscallArgs) int
+
+// syscallEntry is a system call known to the dispatcher
+type syscallEntry struct {
+	name    string
+	handler SyscallHandler
+}
+
+// syscallTable maps system call numbers to their handlers
+var syscallTable = make(map[int]syscallEntry)
+
+// syscallRegs holds the user registers as they were when the system call
+// being carried out was made, before the program counter moved on
+var syscallRegs [machine.NumTotalRegs]uint32
+
+// Implemented in syscall-impl.go
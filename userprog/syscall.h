@@ -45,6 +45,10 @@
 
 #define SysCall_PrintIntHex  	20
 
+#define SysCall_Dup		21
+#define SysCall_Seek		22
+#define SysCall_Stat		23
+
 #define SysCall_NumInstr	50
 
 #ifndef IN_ASM
@@ -103,16 +107,20 @@ typedef int OpenFileId;
 #define ConsoleInput	0
 #define ConsoleOutput	1
 
-/* Create a Nachos file, with "name" */
-void syscall_wrapper_Create(char *name);
+/* Create a Nachos file, with "name".
+ * Return 0, or -1 if the file can't be created.
+ */
+int syscall_wrapper_Create(char *name);
 
 /* Open the Nachos file "name", and return an "OpenFileId" that can
- * be used to read and write to the file.
+ * be used to read and write to the file, or -1 if there is no such file.
  */
 OpenFileId syscall_wrapper_Open(char *name);
 
-/* Write "size" bytes from "buffer" to the open file. */
-void syscall_wrapper_Write(char *buffer, int size, OpenFileId id);
+/* Write "size" bytes from "buffer" to the open file.
+ * Return the number of bytes written, or -1 if "buffer" is bad.
+ */
+int syscall_wrapper_Write(char *buffer, int size, OpenFileId id);
 
 /* Read "size" bytes from the open file into "buffer".
  * Return the number of bytes actually read -- if the open file isn't
@@ -122,8 +130,50 @@ void syscall_wrapper_Write(char *buffer, int size, OpenFileId id);
  */
 int syscall_wrapper_Read(char *buffer, int size, OpenFileId id);
 
-/* Close the file, we're done reading and writing to it. */
-void syscall_wrapper_Close(OpenFileId id);
+/* Close the file, we're done reading and writing to it.
+ * Return 0, or -1 if "id" is not an open file.
+ *
+ * Every file a process still has open is closed when it exits.
+ */
+int syscall_wrapper_Close(OpenFileId id);
+
+/* The most files a process can have open at the same time, counting
+ * ConsoleInput and ConsoleOutput.
+ */
+#define MaxOpenFiles	16
+
+/* Return a new "OpenFileId" for the open file "id", or -1 if "id" is not
+ * an open file or too many files are open.  Both ids share the position
+ * in the file, as do the ids a child inherits from its parent in Fork.
+ */
+OpenFileId syscall_wrapper_Dup(OpenFileId id);
+
+/* Where Seek counts "offset" from */
+#define SeekSet		0	/* the beginning of the file */
+#define SeekCur		1	/* the current position */
+#define SeekEnd		2	/* the end of the file */
+
+/* Move the position in the open file "id" to "offset" bytes from
+ * "whence".  Return the new position, or -1 if "id" is not an open file,
+ * is the console, or the position would be negative.
+ */
+int syscall_wrapper_Seek(OpenFileId id, int offset, int whence);
+
+/* Types of open files, as given by Stat */
+#define FileTypeRegular	0
+#define FileTypeConsole	1
+
+/* What Stat tells about an open file */
+typedef struct {
+    int size;		/* number of bytes in the file */
+    int type;		/* FileTypeRegular or FileTypeConsole */
+    int numSectors;	/* number of disk sectors holding the file */
+} FileStat;
+
+/* Fill in "stat" for the open file "id".
+ * Return 0, or -1 if "id" is not an open file or "stat" is bad.
+ */
+int syscall_wrapper_Stat(OpenFileId id, FileStat *stat);
 
 
 
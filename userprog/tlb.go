@@ -0,0 +1,111 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package userprog
+
+import (
+	"github.com/yashsriv/go-nachos/global"
+	"github.com/yashsriv/go-nachos/utils"
+)
+
+// Routines to manage the TLB of the machine, when it has one.
+//
+// The TLB only ever holds translations of one address space, the one of
+// the process running on the CPU; it is flushed whenever some other
+// process gets to run.  The hardware sets the use and dirty bits in the
+// TLB entry, so they have to be copied back to the page table before the
+// kernel looks at them.
+
+// tlbSpace is the address space whose translations are in the TLB
+var tlbSpace *ProcessAddressSpace
+
+// tlbNext is the TLB entry to be replaced next, when none is free
+var tlbNext = 0
+
+// tlbEnabled checks whether the machine translates through a TLB
+func tlbEnabled() bool {
+	return global.Machine.TLB() != nil
+}
+
+// writeBackTLBEntry copies the use and dirty bits of a TLB entry back to
+// the page table entry it was loaded from.
+func writeBackTLBEntry(tlbEntry *utils.TranslationEntry) {
+	if !tlbEntry.Valid || tlbSpace == nil {
+		return
+	}
+	entry := &tlbSpace.kernelPageTable[tlbEntry.VirtualPage]
+	if entry.Valid && entry.PhysicalPage == tlbEntry.PhysicalPage {
+		entry.Use = entry.Use || tlbEntry.Use
+		entry.Dirty = entry.Dirty || tlbEntry.Dirty
+	}
+	tlbEntry.Use = false // so that clearing it in the page table sticks
+}
+
+// syncTLB brings the page table up to date with the use and dirty bits in
+// the TLB
+func syncTLB() {
+	if !tlbEnabled() {
+		return
+	}
+	tlb := global.Machine.TLB()
+	for i := range tlb {
+		writeBackTLBEntry(&tlb[i])
+	}
+}
+
+// flushTLB empties the TLB, after saving what it knows to the page table
+func flushTLB() {
+	if !tlbEnabled() {
+		return
+	}
+	utils.Debug('a', "Flushing the TLB\n")
+	tlb := global.Machine.TLB()
+	for i := range tlb {
+		writeBackTLBEntry(&tlb[i])
+		tlb[i].Valid = false
+	}
+	tlbSpace = nil
+}
+
+// invalidateTLBEntry drops the translation of virtual page "vpn" of
+// "space" from the TLB, if it is there.  Used when the page table entry
+// changes under the TLB.
+func invalidateTLBEntry(space *ProcessAddressSpace, vpn uint32) {
+	if !tlbEnabled() || space != tlbSpace {
+		return
+	}
+	tlb := global.Machine.TLB()
+	for i := range tlb {
+		if tlb[i].Valid && tlb[i].VirtualPage == vpn {
+			writeBackTLBEntry(&tlb[i])
+			tlb[i].Valid = false
+		}
+	}
+}
+
+// loadTLBEntry copies the translation of virtual page "vpn" of "space"
+// from its page table into the TLB, replacing some other entry if the TLB
+// is full.  The page must be in memory.
+//
+// Entries are replaced in FIFO order.
+func loadTLBEntry(space *ProcessAddressSpace, vpn uint32) {
+	utils.Assert(space == tlbSpace, "Only the running address space can be in the TLB")
+	utils.Assert(space.kernelPageTable[vpn].Valid, "Only a page in memory can be in the TLB")
+
+	tlb := global.Machine.TLB()
+	victim := -1
+	for i := range tlb {
+		if !tlb[i].Valid {
+			victim = i
+			break
+		}
+	}
+	if victim == -1 {
+		victim = tlbNext
+		tlbNext = (tlbNext + 1) % len(tlb)
+		writeBackTLBEntry(&tlb[victim])
+	}
+	utils.Debug('a', "Loading virtual page %d into TLB entry %d\n", vpn, victim)
+	tlb[victim] = space.kernelPageTable[vpn]
+	tlb[victim].Use = false
+}
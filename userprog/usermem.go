@@ -0,0 +1,118 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package userprog
+
+import (
+	"fmt"
+
+	"github.com/yashsriv/go-nachos/global"
+	"github.com/yashsriv/go-nachos/machine"
+)
+
+// Routines for the kernel to move data in and out of the memory of the
+// current user program, typically the buffers and strings passed as
+// system call arguments.
+//
+// Addresses are translated through the page table of the current
+// process, by the kernel itself.  Pages which are not in memory are
+// brought in, and copy-on-write pages are copied before being written,
+// just as for the user program.  An address which the program could not
+// have used fails with an error, instead of raising an exception inside
+// the kernel.
+
+// userPages calls "do" on each piece of the "size" bytes of user memory
+// starting at "vaddr" which lies within one page, with the main memory
+// backing it.
+func userPages(vaddr uint32, size int, writing bool, do func(offset int, mem []byte)) error {
+	if err := checkRange(vaddr, size); err != nil {
+		return err
+	}
+	space := global.CurrentThread.Space().(*ProcessAddressSpace)
+	mainMemory := global.Machine.GetMainMemory()
+	for offset := 0; offset < size; {
+		addr := vaddr + uint32(offset)
+		physAddr, ok := space.translate(addr, writing)
+		if !ok {
+			return fmt.Errorf("Bad user address 0x%x", addr)
+		}
+		n := int(machine.PageSize - addr%machine.PageSize) // bytes left in the page
+		if n > size-offset {
+			n = size - offset
+		}
+		do(offset, mainMemory[physAddr:physAddr+uint32(n)])
+		offset += n
+	}
+	return nil
+}
+
+// checkRange makes sure the "size" bytes starting at "vaddr" lie within
+// the address space of the current process, so that nothing is allocated
+// on behalf of a size the program could never have asked for.
+func checkRange(vaddr uint32, size int) error {
+	space := global.CurrentThread.Space().(*ProcessAddressSpace)
+	end := uint64(space.numVirtualPages) * uint64(machine.PageSize)
+	if size < 0 || uint64(vaddr)+uint64(size) > end {
+		return fmt.Errorf("Bad user range 0x%x+%d", vaddr, size)
+	}
+	return nil
+}
+
+// CopyIn returns the "size" bytes of user memory starting at "vaddr".
+func CopyIn(vaddr uint32, size int) ([]byte, error) {
+	var buf []byte // only grows once userPages has checked the range
+	err := userPages(vaddr, size, false, func(offset int, mem []byte) {
+		buf = append(buf, mem...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// CopyOut writes "buf" to user memory starting at "vaddr".  On error, part
+// of "buf" may have been written.
+func CopyOut(vaddr uint32, buf []byte) error {
+	return userPages(vaddr, len(buf), true, func(offset int, mem []byte) {
+		copy(mem, buf[offset:])
+	})
+}
+
+// CopyInString returns the NUL-terminated string starting at user address
+// "vaddr".  The string may be at most "maxLen" bytes long, not counting
+// the NUL.
+func CopyInString(vaddr uint32, maxLen int) (string, error) {
+	var buf []byte
+	for len(buf) <= maxLen {
+		// Never read past the end of the page; what follows may be bad
+		// even though the string is fine
+		n := int(machine.PageSize - (vaddr+uint32(len(buf)))%machine.PageSize)
+		if n > maxLen+1-len(buf) {
+			n = maxLen + 1 - len(buf)
+		}
+		chunk, err := CopyIn(vaddr+uint32(len(buf)), n)
+		if err != nil {
+			return "", err
+		}
+		for i, ch := range chunk {
+			if ch == 0 {
+				return string(append(buf, chunk[:i]...)), nil
+			}
+		}
+		buf = append(buf, chunk...)
+	}
+	return "", fmt.Errorf("String at user address 0x%x is longer than %d bytes", vaddr, maxLen)
+}
+
+// CheckWritable makes sure the program could write the "size" bytes of
+// user memory starting at "vaddr", so that data meant for them is not
+// fetched only to be lost.  Pages are brought in as for CopyOut.
+func CheckWritable(vaddr uint32, size int) error {
+	return userPages(vaddr, size, true, func(offset int, mem []byte) {})
+}
+
+// CopyOutString writes "s" to user memory starting at "vaddr", followed by
+// a NUL.
+func CopyOutString(vaddr uint32, s string) error {
+	return CopyOut(vaddr, append([]byte(s), 0))
+}
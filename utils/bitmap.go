@@ -0,0 +1,92 @@
+// Copyright (c) 1992-1993 The Regents of the University of California.
+// All rights reserved.
+
+package utils
+
+import "fmt"
+
+// BitMap defines a bitmap -- an array of bits each of which
+// can be either on or off.
+//
+// Represented as an array of bytes, each of which holds 8 bits.
+// Used to keep track of free resources such as physical page frames
+// or disk sectors.
+type BitMap struct {
+	numBits int    // number of bits in the bitmap
+	bits    []byte // bit storage
+}
+
+// Init initializes a bitmap with "numItems" bits, so that every bit is clear.
+func (b *BitMap) Init(numItems int) {
+	b.numBits = numItems
+	b.bits = make([]byte, (numItems+7)/8)
+}
+
+// Mark sets the "nth" bit in a bitmap.
+func (b *BitMap) Mark(which int) {
+	Assert(which >= 0 && which < b.numBits, "Bit to mark should be within the bitmap")
+	b.bits[which/8] |= 1 << uint(which%8)
+}
+
+// Clear clears the "nth" bit in a bitmap.
+func (b *BitMap) Clear(which int) {
+	Assert(which >= 0 && which < b.numBits, "Bit to clear should be within the bitmap")
+	b.bits[which/8] &^= 1 << uint(which%8)
+}
+
+// Test returns true if the "nth" bit is set.
+func (b *BitMap) Test(which int) bool {
+	Assert(which >= 0 && which < b.numBits, "Bit to test should be within the bitmap")
+	return b.bits[which/8]&(1<<uint(which%8)) != 0
+}
+
+// Find returns the number of the first bit which is clear.
+// As a side effect, set the bit (mark it as in use).
+// (In other words, find and allocate a bit.)
+//
+// If no bits are clear, return -1.
+func (b *BitMap) Find() int {
+	for i := 0; i < b.numBits; i++ {
+		if !b.Test(i) {
+			b.Mark(i)
+			return i
+		}
+	}
+	return -1
+}
+
+// NumClear returns the number of clear bits in the bitmap.
+// (In other words, how many bits are unallocated?)
+func (b *BitMap) NumClear() int {
+	count := 0
+	for i := 0; i < b.numBits; i++ {
+		if !b.Test(i) {
+			count++
+		}
+	}
+	return count
+}
+
+// Print prints the contents of the bitmap, for debugging.
+func (b *BitMap) Print() {
+	fmt.Printf("Bitmap set:\n")
+	for i := 0; i < b.numBits; i++ {
+		if b.Test(i) {
+			fmt.Printf("%d, ", i)
+		}
+	}
+	fmt.Printf("\n")
+}
+
+// FetchFrom initializes the contents of the bitmap from "data", as
+// stored in a file by WriteBack.
+func (b *BitMap) FetchFrom(data []byte) {
+	copy(b.bits, data)
+}
+
+// WriteBack returns the contents of the bitmap, to be stored in a file.
+func (b *BitMap) WriteBack() []byte {
+	data := make([]byte, len(b.bits))
+	copy(data, b.bits)
+	return data
+}
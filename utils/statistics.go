@@ -3,7 +3,10 @@
 
 package utils
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
 // Statistics defines the statistics that are to be kept
 // about Nachos behavior -- how much time (ticks) elapsed, how
@@ -13,13 +16,30 @@ type Statistics struct {
 	IdleTicks              int // Time spent idle (no threads to run)
 	SystemTicks            int // Time spent executing system code
 	UserTicks              int // Time spent executing user code
-	NumDiskReads           int // number of disk read requests
-	NumDiskWrites          int // number of disk write requests
+	NumDiskReads           int // number of disk read requests, including paging
+	NumDiskWrites          int // number of disk write requests, including paging
+	DiskLatency            int // total time from disk request to completion, including queueing
+	DiskSeekDistance       int // total number of tracks the disk head moved over
+	MaxDiskWait            int // longest time a disk request waited in the queue
+	DiskSchedulingPolicy   string
+	NumPagingReads         int // number of disk reads to bring in a page from swap
+	NumPagingWrites        int // number of disk writes to save an evicted page to swap
 	NumConsoleCharsRead    int // number of characters read from the keyboard
 	NumConsoleCharsWritten int // number of characters written to the display
 	NumPageFaults          int // number of virtual memory page faults
+	NumPageReplacements    int // number of pages evicted to make room for another
+	NumCOWFaults           int // number of writes to pages shared copy-on-write
+	NumCOWFramesSaved      int // number of frames shared after fork and not (yet) copied
+	NumTLBHits             int // number of translations found in the TLB
+	NumTLBMisses           int // number of translations missing from the TLB
+	PageReplacementPolicy  string
+	NumFreeFrames          int // number of physical page frames not in use
+	NumUsedFrames          int // number of physical page frames in use
 	NumPacketsSent         int // number of packets sent over the network
 	NumPacketsRecvd        int // number of packets received over the network
+
+	// Faults and replacements under each page replacement policy used
+	PagingByPolicy map[string]*PagingStatistics
 }
 
 // Print performance metrics, when we've finished everything
@@ -27,14 +47,72 @@ type Statistics struct {
 func (stats *Statistics) Print() {
 	fmt.Printf("Ticks: total %d, idle %d, system %d, user %d\n", stats.TotalTicks,
 		stats.IdleTicks, stats.SystemTicks, stats.UserTicks)
-	fmt.Printf("Disk I/O: reads %d, writes %d\n", stats.NumDiskReads, stats.NumDiskWrites)
+	fmt.Printf("Disk I/O: reads %d, writes %d\n", stats.NumDiskReads-stats.NumPagingReads,
+		stats.NumDiskWrites-stats.NumPagingWrites)
+	fmt.Printf("Disk scheduling: average latency %d, seek distance %d, maximum wait %d (policy %s)\n",
+		stats.averageDiskLatency(), stats.DiskSeekDistance, stats.MaxDiskWait, stats.DiskSchedulingPolicy)
+	fmt.Printf("Paging I/O: reads %d, writes %d\n", stats.NumPagingReads, stats.NumPagingWrites)
 	fmt.Printf("Console I/O: reads %d, writes %d\n", stats.NumConsoleCharsRead,
 		stats.NumConsoleCharsWritten)
-	fmt.Printf("Paging: faults %d\n", stats.NumPageFaults)
+	fmt.Printf("Paging: faults %d, replacements %d (policy %s)\n", stats.NumPageFaults,
+		stats.NumPageReplacements, stats.PageReplacementPolicy)
+	policies := make([]string, 0, len(stats.PagingByPolicy))
+	for policy := range stats.PagingByPolicy {
+		policies = append(policies, policy)
+	}
+	sort.Strings(policies)
+	for _, policy := range policies {
+		fmt.Printf("Paging with %s: faults %d, replacements %d\n", policy,
+			stats.PagingByPolicy[policy].NumPageFaults, stats.PagingByPolicy[policy].NumPageReplacements)
+	}
+	fmt.Printf("Memory: frames free %d, used %d\n", stats.NumFreeFrames, stats.NumUsedFrames)
+	fmt.Printf("Copy-on-write: faults %d, frames saved %d\n", stats.NumCOWFaults, stats.NumCOWFramesSaved)
+	fmt.Printf("TLB: hits %d, misses %d\n", stats.NumTLBHits, stats.NumTLBMisses)
 	fmt.Printf("Network I/O: packets received %d, sent %d\n", stats.NumPacketsRecvd,
 		stats.NumPacketsSent)
 }
 
+// PagingStatistics counts the paging done under one page replacement
+// policy
+type PagingStatistics struct {
+	NumPageFaults       int
+	NumPageReplacements int
+}
+
+// policyPaging returns the counters of the page replacement policy in use
+func (stats *Statistics) policyPaging() *PagingStatistics {
+	if stats.PagingByPolicy == nil {
+		stats.PagingByPolicy = make(map[string]*PagingStatistics)
+	}
+	paging, ok := stats.PagingByPolicy[stats.PageReplacementPolicy]
+	if !ok {
+		paging = &PagingStatistics{}
+		stats.PagingByPolicy[stats.PageReplacementPolicy] = paging
+	}
+	return paging
+}
+
+// CountPageFault records a page fault, under the policy in use
+func (stats *Statistics) CountPageFault() {
+	stats.NumPageFaults++
+	stats.policyPaging().NumPageFaults++
+}
+
+// CountPageReplacement records a page eviction, under the policy in use
+func (stats *Statistics) CountPageReplacement() {
+	stats.NumPageReplacements++
+	stats.policyPaging().NumPageReplacements++
+}
+
+// averageDiskLatency returns the average time a disk request took,
+// queueing included
+func (stats *Statistics) averageDiskLatency() int {
+	if stats.NumDiskReads+stats.NumDiskWrites == 0 {
+		return 0
+	}
+	return stats.DiskLatency / (stats.NumDiskReads + stats.NumDiskWrites)
+}
+
 // Constants used to reflect the relative time an operation would
 // take in a real system.  A "tick" is a just a unit of time -- if you
 // like, a microsecond.